@@ -0,0 +1,41 @@
+package pyver
+
+import "testing"
+
+func TestAccessors(t *testing.T) {
+	tests := []struct {
+		input               string
+		public, base        string
+		pre, post, dev      bool
+		major, minor, micro int
+	}{
+		{"1.2.3", "1.2.3", "1.2.3", false, false, false, 1, 2, 3},
+		{"1.2.3+abc", "1.2.3", "1.2.3", false, false, false, 1, 2, 3},
+		{"1!1.2.3dev1+abc", "1!1.2.3.dev1", "1!1.2.3", true, false, true, 1, 2, 3},
+		{"1.2.3a1", "1.2.3a1", "1.2.3", true, false, false, 1, 2, 3},
+		{"1.2.3rc1.post2", "1.2.3rc1.post2", "1.2.3", true, true, false, 1, 2, 3},
+		{"1.2.3.post0", "1.2.3.post0", "1.2.3", false, true, false, 1, 2, 3},
+		{"1.0.post1.dev0", "1.0.post1.dev0", "1.0", true, true, true, 1, 0, 0},
+		{"2", "2", "2", false, false, false, 2, 0, 0},
+		{"1!2.0.0.post0", "1!2.0.0.post0", "1!2.0.0", false, true, false, 2, 0, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			v := MustParse(tc.input)
+			if got := v.Public(); got != tc.public {
+				t.Errorf("Public() = %q, want %q", got, tc.public)
+			}
+			if got := v.BaseVersion(); got != tc.base {
+				t.Errorf("BaseVersion() = %q, want %q", got, tc.base)
+			}
+			if v.IsPrerelease() != tc.pre || v.IsPostrelease() != tc.post || v.IsDevrelease() != tc.dev {
+				t.Errorf("Is{Pre,Post,Dev}release() = %v,%v,%v, want %v,%v,%v",
+					v.IsPrerelease(), v.IsPostrelease(), v.IsDevrelease(), tc.pre, tc.post, tc.dev)
+			}
+			if v.Major() != tc.major || v.Minor() != tc.minor || v.Micro() != tc.micro {
+				t.Errorf("Major/Minor/Micro() = %d.%d.%d, want %d.%d.%d",
+					v.Major(), v.Minor(), v.Micro(), tc.major, tc.minor, tc.micro)
+			}
+		})
+	}
+}
@@ -0,0 +1,820 @@
+package pyver
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The set operations on specifier sets work on regions: the versions a set
+// contains, laid out along the PEP 440 order. Ordered comparisons only look
+// at public versions, so a region is a sorted list of bounds, each a public
+// version or the top of a release class, with what it holds at each bound
+// and between two bounds.
+//
+// Between bounds a region holds some of the eight kinds of version, told
+// apart by whether they are pre-releases, post-releases and local versions.
+// That is enough for the clauses that exclude one kind of version of a
+// single release class: "<1.0" excludes the pre-releases of 1.0 and ">1.0"
+// its post-releases and local versions, which lie between 1.0 and the top
+// of its class. At a bound it holds the public version and a set of local
+// labels, for clauses such as "==1.0+abc".
+//
+// Every clause except === has an exact region. A === clause is taken to
+// match the versions equal to the one it names, which is more than it
+// does: "===1.0" does not match "1.0.0". That is safe for emptiness, but a
+// set is only known to be a subset of one with a === clause if it has the
+// clause too (see arbitraryIn).
+
+// kinds is a set of kinds of version. Bit pre|post<<1|local<<2 stands for
+// the versions that are pre-releases (including dev releases) if pre is 1,
+// post-releases if post is 1 and have a local label if local is 1.
+type kinds uint8
+
+const (
+	allKinds   kinds = 0xff
+	preKinds   kinds = 0xaa
+	postKinds  kinds = 0xcc
+	localKinds kinds = 0xf0
+)
+
+// kindOf returns the set of v's kind alone.
+func kindOf(v Version) kinds {
+	i := 0
+	if v.IsPrerelease() {
+		i |= 1
+	}
+	if v.IsPostrelease() {
+		i |= 2
+	}
+	if len(v.Local) > 0 {
+		i |= 4
+	}
+	return 1 << i
+}
+
+// withLocal returns the kinds of k with a local label added.
+func withLocal(k kinds) kinds {
+	return k << 4
+}
+
+// A bound is a public version, or with top set, the top of v's release
+// class: above every version with v's epoch and release, such as
+// "1.0.post9+abc" for 1.0, and below every later version.
+type bound struct {
+	v   Version
+	top bool
+}
+
+// classOf returns v's epoch and release, for comparison only.
+func classOf(v Version) Version {
+	return Version{Epoch: v.Epoch, Release: v.Release, wide: v.wide}
+}
+
+func compareBound(a, b bound) int {
+	if !a.top && !b.top {
+		return compareGoNative(a.v, b.v)
+	}
+	c := compareGoNative(classOf(a.v), classOf(b.v))
+	switch {
+	case c != 0 || a.top == b.top:
+		return c
+	case a.top:
+		return 1
+	}
+	return -1
+}
+
+// firstDev returns the first dev release of v's release class, which sorts
+// before every other version of it: "1.4rc1" -> "1.4.dev0".
+func firstDev(v Version) Version {
+	w := v.release()
+	w.setDev(numeral{})
+	return finish(w)
+}
+
+// atBound holds the versions a region has at a bound: the public version
+// if bare, and its local versions whose labels are in labels or, if
+// except, are not. It is empty at the top of a class.
+type atBound struct {
+	bare, except bool
+	labels       [][]string
+}
+
+var wholeBound = atBound{bare: true, except: true}
+
+func (a atBound) hasLabel(l []string) bool {
+	return slices.ContainsFunc(a.labels, func(m []string) bool { return compareLocal(m, l) == 0 }) != a.except
+}
+
+func (a atBound) combine(b atBound, op func(x, y bool) bool) atBound {
+	r := atBound{bare: op(a.bare, b.bare), except: op(a.except, b.except)}
+	for _, l := range slices.Concat(a.labels, b.labels) {
+		if op(a.hasLabel(l), b.hasLabel(l)) != r.hasLabel(l) {
+			r.labels = append(r.labels, l)
+		}
+	}
+	return r
+}
+
+// witness returns a version at b, a public version, if there is one.
+func (a atBound) witness(v Version) (Version, bool) {
+	if a.bare {
+		return v, true
+	}
+	var l []string
+	switch {
+	case a.except:
+		// One of len(labels)+1 distinct labels is not excluded.
+		for i := 0; l == nil; i++ {
+			if c := []string{strconv.Itoa(i)}; a.hasLabel(c) {
+				l = c
+			}
+		}
+	case len(a.labels) > 0:
+		l = a.labels[0]
+	default:
+		return Version{}, false
+	}
+	v.Local = l
+	return finish(v), true
+}
+
+// atGap returns what a gap holding the kinds k holds at b.
+func atGap(k kinds, b bound) atBound {
+	if b.top {
+		return atBound{}
+	}
+	bare := kindOf(b.v)
+	return atBound{bare: k&bare != 0, except: k&withLocal(bare) != 0}
+}
+
+// A region is a set of versions. It holds at[i] at bounds[i], and the
+// kinds gaps[i] strictly between bounds[i-1] and bounds[i], where the
+// first and last gaps are unbounded below and above.
+type region struct {
+	bounds []bound
+	at     []atBound
+	gaps   []kinds
+}
+
+// everything returns the versions of the kinds k.
+func everything(k kinds) region {
+	return region{gaps: []kinds{k}}
+}
+
+// split returns the region holding the kinds below under b, at at b and
+// the kinds above over it.
+func split(b bound, below kinds, at atBound, above kinds) region {
+	return region{bounds: []bound{b}, at: []atBound{at}, gaps: []kinds{below, above}}
+}
+
+// point returns the region of v alone, and its local versions if v has no
+// local label and anyLocal is set.
+func point(v Version, anyLocal bool) region {
+	if len(v.Local) > 0 {
+		return split(bound{v: v.WithoutLocal()}, 0, atBound{labels: [][]string{v.Local}}, 0)
+	}
+	return split(bound{v: v}, 0, atBound{bare: true, except: anyLocal}, 0)
+}
+
+// class returns the versions with v's epoch and release.
+func class(v Version) region {
+	return region{
+		bounds: []bound{{v: firstDev(v)}, {v: finish(v.release()), top: true}},
+		at:     []atBound{wholeBound, {}},
+		gaps:   []kinds{0, allKinds, 0},
+	}
+}
+
+// prefixRegion returns the versions prefixMatch matches, from the first dev
+// release of the prefix to that of the next: "1.4" -> [1.4.dev0, 1.5.dev0).
+// A prefix that is not a release matches nothing.
+func prefixRegion(spec string) region {
+	p, err := parseGoNative(spec)
+	if err != nil || p.PreKind != "" || p.HasPost || p.HasDev || len(p.Local) > 0 {
+		return everything(0)
+	}
+	from := split(bound{v: firstDev(p)}, 0, wholeBound, allKinds)
+	to := split(bound{v: firstDev(p.BumpRelease(len(p.Release) - 1))}, allKinds, atBound{}, 0)
+	return from.intersect(to)
+}
+
+// combine returns the region holding the versions for which op, given
+// whether they are in a and in b, is true.
+func combine(a, b region, op func(x, y bool) bool) region {
+	var r region
+	for i, j := 0, 0; ; {
+		var k kinds
+		for bit := kinds(1); bit != 0; bit <<= 1 {
+			if op(a.gaps[i]&bit != 0, b.gaps[j]&bit != 0) {
+				k |= bit
+			}
+		}
+		r.gaps = append(r.gaps, k)
+
+		var c int
+		switch {
+		case i == len(a.bounds) && j == len(b.bounds):
+			return r
+		case i == len(a.bounds):
+			c = 1
+		case j == len(b.bounds):
+			c = -1
+		default:
+			c = compareBound(a.bounds[i], b.bounds[j])
+		}
+		var bd bound
+		var x, y atBound
+		if c <= 0 {
+			bd, x = a.bounds[i], a.at[i]
+			i++
+		} else {
+			x = atGap(a.gaps[i], b.bounds[j])
+		}
+		if c >= 0 {
+			bd, y = b.bounds[j], b.at[j]
+			j++
+		} else {
+			y = atGap(b.gaps[j], bd)
+		}
+		r.bounds = append(r.bounds, bd)
+		if bd.top {
+			r.at = append(r.at, atBound{})
+		} else {
+			r.at = append(r.at, x.combine(y, op))
+		}
+	}
+}
+
+func (r region) intersect(o region) region {
+	return combine(r, o, func(x, y bool) bool { return x && y })
+}
+
+func (r region) union(o region) region {
+	return combine(r, o, func(x, y bool) bool { return x || y })
+}
+
+func (r region) minus(o region) region {
+	return combine(r, o, func(x, y bool) bool { return x && !y })
+}
+
+func (r region) isEmpty() bool {
+	_, ok := r.witness()
+	return !ok
+}
+
+func (r region) subsetOf(o region) bool {
+	return r.minus(o).isEmpty()
+}
+
+func (r region) equal(o region) bool {
+	return combine(r, o, func(x, y bool) bool { return x != y }).isEmpty()
+}
+
+// contains reports whether v is in r.
+func (r region) contains(v Version) bool {
+	i, found := slices.BinarySearchFunc(r.bounds, bound{v: public(v)}, compareBound)
+	switch {
+	case !found:
+		return r.gaps[i]&kindOf(v) != 0
+	case len(v.Local) > 0:
+		return r.at[i].hasLabel(v.Local)
+	}
+	return r.at[i].bare
+}
+
+// gap returns the bounds of gap i, nil where it is unbounded.
+func (r region) gap(i int) (lo, hi *bound) {
+	if i > 0 {
+		lo = &r.bounds[i-1]
+	}
+	if i < len(r.bounds) {
+		hi = &r.bounds[i]
+	}
+	return lo, hi
+}
+
+// witness returns a version in r, or false if r is empty.
+func (r region) witness() (Version, bool) {
+	for i, k := range r.gaps {
+		if k != 0 {
+			for _, v := range between(r.gap(i)) {
+				switch {
+				case k&kindOf(v) != 0:
+					return v, true
+				case k&withLocal(kindOf(v)) != 0:
+					v.Local = []string{"0"}
+					return finish(v), true
+				}
+			}
+		}
+		if i < len(r.bounds) && !r.bounds[i].top {
+			if v, ok := r.at[i].witness(r.bounds[i].v); ok {
+				return v, true
+			}
+		}
+	}
+	return Version{}, false
+}
+
+// present returns the kinds of version in gap i.
+func (r region) present(i int) kinds {
+	var k kinds
+	for _, v := range between(r.gap(i)) {
+		k |= kindOf(v) | withLocal(kindOf(v))
+	}
+	return k
+}
+
+// between returns public versions strictly between lo and hi, one of each
+// kind that occurs there.
+//
+// If lo and hi are in different release classes, another class lies
+// between them, holding every kind: 1.0.0.1 lies between 1.0 and 1.0.1, and
+// 1.0.1 between 1.0 and 1.1. Within a class, a version between lo and hi
+// can be moved towards lo or hi without changing its kind until each of
+// its pre-, post- and dev-release segments is absent, zero, "a0" or equal
+// or next to that of lo or hi; see classCandidates.
+func between(lo, hi *bound) []Version {
+	if c, ok := classBetween(lo, hi); ok {
+		dev, post, postDev := c.release(), c.release(), c.release()
+		dev.setDev(numeral{})
+		post.setPost(numeral{})
+		postDev.setPost(numeral{})
+		postDev.setDev(numeral{})
+		return []Version{finish(dev), finish(c), finish(post), finish(postDev)}
+	}
+	var vs []Version
+	var seen kinds
+	for _, v := range classCandidates(lo, hi) {
+		b := bound{v: v}
+		if k := kindOf(v); seen&k == 0 && (lo == nil || compareBound(*lo, b) < 0) && compareBound(b, *hi) < 0 {
+			seen |= k
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
+
+// classBetween returns a release class strictly between those of lo and
+// hi, as its final release, if there is one.
+func classBetween(lo, hi *bound) (Version, bool) {
+	switch {
+	case lo == nil && hi == nil:
+		return finish(Version{Release: []int{0}}), true
+	case hi == nil:
+		return newClass(lo.v.epochNumeral(), append(releaseNumerals(lo.v), numeral{n: 1})), true
+	case lo == nil:
+		// The class of hi without its last release component, or 1 in
+		// epoch 0.
+		c := releaseNumerals(hi.v)
+		if len(c) > 0 {
+			return newClass(hi.v.epochNumeral(), c[:len(c)-1]), true
+		}
+		if !hi.v.epochNumeral().isZero() {
+			return newClass(numeral{}, []numeral{{n: 1}}), true
+		}
+		return Version{}, false
+	}
+	if compareGoNative(classOf(lo.v), classOf(hi.v)) >= 0 {
+		return Version{}, false
+	}
+	a, c := releaseNumerals(lo.v), releaseNumerals(hi.v)
+	i := 0
+	for i < len(a) && i < len(c) && a[i].compare(c[i]) == 0 {
+		i++
+	}
+	if i < len(a) || lo.v.epochNumeral().compare(hi.v.epochNumeral()) != 0 {
+		// Anything above a in its epoch will do: 1.0.1 for 1.0 and 1.1.
+		return newClass(lo.v.epochNumeral(), append(a, numeral{n: 1})), true
+	}
+	// a is a prefix of c, which goes on with zeros and then a larger
+	// component: 1.0.0.1 for 1.0 and 1.0.1.
+	j := i
+	for c[j].isZero() {
+		j++
+	}
+	return newClass(lo.v.epochNumeral(), slices.Concat(a, make([]numeral, j-i+1), []numeral{{n: 1}})), true
+}
+
+// releaseNumerals returns v's release without trailing zeros.
+func releaseNumerals(v Version) []numeral {
+	ns := make([]numeral, releaseLen(v))
+	for i := range ns {
+		ns[i] = v.releaseNumeral(i)
+	}
+	return ns
+}
+
+// newClass returns the final release with the given epoch and release.
+func newClass(epoch numeral, release []numeral) Version {
+	var v Version
+	v.setEpoch(epoch)
+	for _, n := range release {
+		v.appendRelease(n)
+	}
+	if len(release) == 0 {
+		v.appendRelease(numeral{})
+	}
+	return finish(v)
+}
+
+// classCandidates returns versions of the release class lo and hi share,
+// combining pre-, post- and dev-release segments that are
+// absent, zero, "a0", or those of lo and hi or just after lo's.
+func classCandidates(lo, hi *bound) []Version {
+	type segment struct {
+		kind string
+		n    numeral
+		ok   bool
+	}
+	pres := []segment{{}, {kind: "a", ok: true}}
+	posts := []segment{{}, {ok: true}}
+	devs := []segment{{}, {ok: true}}
+	c := hi
+	for _, b := range []*bound{lo, hi} {
+		if b == nil || b.top {
+			continue
+		}
+		c = b
+		v := b.v
+		pres = append(pres, segment{v.PreKind, v.preNumeral(), v.PreKind != ""})
+		posts = append(posts, segment{"", v.postNumeral(), v.HasPost})
+		devs = append(devs, segment{"", v.devNumeral(), v.HasDev})
+		if b == lo {
+			if v.PreKind != "" {
+				pres = append(pres, segment{v.PreKind, v.preNumeral().inc(), true})
+			}
+			if v.HasPost {
+				posts = append(posts, segment{"", v.postNumeral().inc(), true})
+			}
+			if v.HasDev {
+				devs = append(devs, segment{"", v.devNumeral().inc(), true})
+			}
+		}
+	}
+	var vs []Version
+	for _, pre := range pres {
+		for _, post := range posts {
+			for _, dev := range devs {
+				v := c.v.release()
+				if pre.ok {
+					v.setPre(pre.kind, pre.n)
+				}
+				if post.ok {
+					v.setPost(post.n)
+				}
+				if dev.ok {
+					v.setDev(dev.n)
+				}
+				vs = append(vs, finish(v))
+			}
+		}
+	}
+	return vs
+}
+
+// region returns the versions sp contains when pre-releases are allowed.
+func (sp Specifier) region() region {
+	b := bound{v: sp.v}
+	switch sp.op {
+	case OpCompatible:
+		return split(b, 0, wholeBound, allKinds).intersect(prefixRegion(compatiblePrefix(sp.version)))
+	case OpEqual, OpNotEqual:
+		var r region
+		if sp.wildcard {
+			r = prefixRegion(strings.TrimSuffix(sp.version, ".*"))
+		} else {
+			r = point(sp.v, true)
+		}
+		if sp.op == OpNotEqual {
+			return everything(allKinds).minus(r)
+		}
+		return r
+	case OpLessEqual:
+		return split(b, allKinds, wholeBound, 0)
+	case OpGreaterEqual:
+		return split(b, 0, wholeBound, allKinds)
+	case OpLess:
+		r := split(b, allKinds, atBound{}, 0)
+		if !sp.v.IsPrerelease() {
+			r = r.minus(class(sp.v).intersect(everything(preKinds)))
+		}
+		return r
+	case OpGreater:
+		excluded := localKinds
+		if !sp.v.IsPostrelease() {
+			excluded |= postKinds
+		}
+		r := split(b, 0, atBound{except: true}, allKinds)
+		return r.minus(class(sp.v).intersect(everything(excluded)))
+	}
+	if v, err := parseGoNative(sp.version); err == nil {
+		return point(v, false)
+	}
+	return everything(0)
+}
+
+// region returns the versions s contains.
+func (s SpecifierSet) region() region {
+	r := everything(allKinds)
+	if !s.PreReleases() {
+		r = everything(allKinds &^ preKinds)
+	}
+	for _, sp := range s.specs {
+		r = r.intersect(sp.region())
+	}
+	return r
+}
+
+// policyFor returns the explicit policy admitting pre-releases or not.
+func policyFor(pre bool) PreReleasePolicy {
+	if pre {
+		return PreReleasesInclude
+	}
+	return PreReleasesExclude
+}
+
+// Intersect returns a set containing the versions both s and o contain:
+// their clauses together, with an explicit pre-release policy where s and
+// o differ on pre-releases, as ">=1.0a1" and "<2" do.
+func (s SpecifierSet) Intersect(o SpecifierSet) SpecifierSet {
+	t := NewSpecifierSet(slices.Concat(s.specs, o.specs)...)
+	if pre := s.PreReleases() && o.PreReleases(); t.PreReleases() != pre {
+		t.policy = policyFor(pre)
+	}
+	return t
+}
+
+// Union returns a set containing the versions s or o contains, or false if
+// no set of clauses does, as for "==1.0" and "==2.0". If one of s and o
+// contains the other (see IsSubsetOf), it is returned as is; otherwise the
+// set is built as Simplify builds one: "~=1.4.2" and "==1.5.*" give
+// "<1.6,>=1.4.2". As that cannot reproduce a === clause, Union reports
+// false for other unions involving one.
+func (s SpecifierSet) Union(o SpecifierSet) (SpecifierSet, bool) {
+	switch {
+	case o.IsSubsetOf(s):
+		return s, true
+	case s.IsSubsetOf(o):
+		return o, true
+	case s.hasArbitrary() || o.hasArbitrary():
+		return SpecifierSet{}, false
+	}
+	return describe(s.region().union(o.region()), true)
+}
+
+// IsEmpty reports whether s contains no version at all, as for
+// ">=1.2,<2,~=2.1". The rules of Contains apply, so ">1.0,<1.0.post1" is
+// empty as ">1.0" excludes the post-releases of 1.0.
+func (s SpecifierSet) IsEmpty() bool {
+	return s.region().isEmpty()
+}
+
+// IsSubsetOf reports whether o contains every version s contains:
+// "~=1.4.2" is a subset of ">=1.4,<1.5". As === matches a string rather
+// than a version, a non-empty s is only a subset of a set with a ===
+// clause if it has the same clause: "==1.0" is not a subset of "===1.0",
+// which does not contain "1.0.0".
+func (s SpecifierSet) IsSubsetOf(o SpecifierSet) bool {
+	if !s.arbitraryIn(o) {
+		return s.IsEmpty()
+	}
+	return s.region().subsetOf(o.region())
+}
+
+// hasArbitrary reports whether s has a === clause.
+func (s SpecifierSet) hasArbitrary() bool {
+	return slices.ContainsFunc(s.specs, func(sp Specifier) bool { return sp.op == OpArbitrary })
+}
+
+// arbitraryIn reports whether s has every === clause of o, with the same
+// string ignoring case. Only then does the region of o, which
+// over-approximates its === clauses, decide whether o contains s.
+func (s SpecifierSet) arbitraryIn(o SpecifierSet) bool {
+	return !slices.ContainsFunc(o.specs, func(a Specifier) bool {
+		return a.op == OpArbitrary && !slices.ContainsFunc(s.specs, func(b Specifier) bool {
+			return b.op == OpArbitrary && strings.ToLower(a.version) == strings.ToLower(b.version)
+		})
+	})
+}
+
+// Simplify returns a set containing the same versions as s in few
+// clauses, rebuilt from the bounds of the versions it contains. It prefers
+// == to wildcards, wildcards to ~=, ~= to other comparisons and those to
+// != wildcards, and only mentions a pre-release when s contains
+// pre-releases:
+//
+//	">=1.0,>=1.2,<3,<2" -> "~=1.2"
+//	">=1.4,<1.5" -> "==1.4.*"
+//	"<1.5,!=1.5,!=2.0" -> "<1.5"
+//	">=1.2,<2,~=2.1" -> "<0"
+//
+// As an empty set stands for no clause, an empty result is written "<0".
+// If s has === clauses, Simplify only drops the other clauses they imply.
+func (s SpecifierSet) Simplify() SpecifierSet {
+	pruned := s.prune()
+	if s.hasArbitrary() {
+		return pruned
+	}
+	_, known := s.preReleases()
+	if t, ok := describe(s.region(), known); ok && t.Len() <= pruned.Len() {
+		return t
+	}
+	return pruned
+}
+
+// prune drops the clauses of s that the others imply, except === clauses.
+func (s SpecifierSet) prune() SpecifierSet {
+	r := s.region()
+	pre, known := s.preReleases()
+	t := s
+	t.specs = slices.SortedFunc(slices.Values(s.specs), func(a, b Specifier) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for i := 0; i < len(t.specs); {
+		u := t
+		u.specs = slices.Delete(slices.Clone(t.specs), i, i+1)
+		p, k := u.preReleases()
+		if t.specs[i].op != OpArbitrary && p == pre && k == known && u.region().equal(r) {
+			t = u
+		} else {
+			i++
+		}
+	}
+	return t
+}
+
+// describe returns a set containing exactly the versions in r, made of
+// clauses comparing with r's bounds, or false if it finds none. Its
+// pre-release policy is auto if that gives the same pre-releases, and the
+// same known, as preReleases.
+func describe(r region, known bool) (SpecifierSet, bool) {
+	if r.isEmpty() {
+		return MustParseSpecifierSet("<0"), true
+	}
+	pre := !r.intersect(everything(preKinds)).isEmpty()
+	base := everything(allKinds)
+	if !pre {
+		base = everything(allKinds &^ preKinds)
+	}
+	type clause struct {
+		sp Specifier
+		r  region
+	}
+	var cs []clause
+	for _, sp := range r.reduce().candidates() {
+		if c := (clause{sp, base.intersect(sp.region())}); r.subsetOf(c.r) {
+			cs = append(cs, c)
+		}
+	}
+	// Try dropping the clauses least preferred first.
+	slices.SortStableFunc(cs, func(a, b clause) int {
+		return cmp.Compare(preference(a.sp, pre), preference(b.sp, pre))
+	})
+	without := func(skip int) region {
+		acc := base
+		for i, c := range cs {
+			if i != skip {
+				acc = acc.intersect(c.r)
+			}
+		}
+		return acc
+	}
+	if !without(-1).equal(r) {
+		return SpecifierSet{}, false
+	}
+	for i := 0; i < len(cs); {
+		if without(i).equal(r) {
+			cs = slices.Delete(cs, i, i+1)
+		} else {
+			i++
+		}
+	}
+	var set SpecifierSet
+	for _, c := range cs {
+		set.add(c.sp)
+	}
+	if p, k := set.preReleases(); p != pre || k != known {
+		set.policy = policyFor(pre)
+	}
+	return set, true
+}
+
+// preference ranks a clause for describe: those mentioning a pre-release
+// when the set contains none come first, then != wildcards, comparisons,
+// ~=, == wildcards and ==.
+func preference(sp Specifier, pre bool) int {
+	switch {
+	case sp.pre && !pre:
+		return 0
+	case sp.wildcard && sp.op == OpNotEqual:
+		return 1
+	case sp.wildcard:
+		return 4
+	case sp.op == OpCompatible:
+		return 3
+	case sp.op == OpEqual:
+		return 5
+	}
+	return 2
+}
+
+// reduce drops the bounds r does not need.
+func (r region) reduce() region {
+	for i := 0; i < len(r.bounds); {
+		s := region{
+			bounds: slices.Delete(slices.Clone(r.bounds), i, i+1),
+			at:     slices.Delete(slices.Clone(r.at), i, i+1),
+			gaps: slices.Concat(r.gaps[:i],
+				[]kinds{r.gaps[i]&r.present(i) | r.gaps[i+1]&r.present(i+1)},
+				r.gaps[i+2:]),
+		}
+		if s.equal(r) {
+			r = s
+		} else {
+			i++
+		}
+	}
+	return r
+}
+
+// candidates returns clauses that may describe r: comparisons with its
+// bounds, their local versions and the final releases of their classes,
+// and the wildcard and ~= clauses ending at those classes.
+func (r region) candidates() []Specifier {
+	var specs []string
+	for i, b := range r.bounds {
+		v := b.v.String()
+		if b.top {
+			specs = append(specs, ">"+v)
+			continue
+		}
+		for _, op := range []Operator{OpGreaterEqual, OpGreater, OpLessEqual, OpLess, OpNotEqual, OpEqual} {
+			specs = append(specs, string(op)+v)
+		}
+		for _, l := range r.at[i].labels {
+			local := v + "+" + strings.Join(l, ".")
+			specs = append(specs, "=="+local, "!="+local)
+		}
+		final := finish(b.v.release()).String()
+		specs = append(specs, ">="+final, "<"+final)
+		p, ok := prefixBelow(b.v)
+		if !ok {
+			continue
+		}
+		specs = append(specs, "=="+p.String()+".*", "!="+p.String()+".*")
+		for _, lo := range r.bounds[:i] {
+			if x, ok := compatibleFrom(lo, p); ok {
+				specs = append(specs, "~="+x.String())
+			}
+		}
+	}
+	var sps []Specifier
+	for _, s := range specs {
+		if sp, err := ParseSpecifier(s); err == nil && !slices.ContainsFunc(sps, sp.Equal) {
+			sps = append(sps, sp)
+		}
+	}
+	return sps
+}
+
+// prefixBelow returns the release prefix P for which ==P.* ends at v's
+// release: "1.5.dev0" -> "1.4", "1.4.1.dev0" -> "1.4.0".
+func prefixBelow(v Version) (Version, bool) {
+	n := releaseLen(v)
+	if n == 0 || v.releaseNumeral(n-1).digits != "" {
+		return Version{}, false
+	}
+	rel := releaseNumerals(v)
+	rel[n-1] = numeral{n: v.Release[n-1] - 1}
+	return newClass(v.epochNumeral(), rel), true
+}
+
+// compatibleFrom returns lo as X with one release component more than p,
+// so that ~=X starts at lo and ends where ==P.* does, if lo's release
+// starts with p: "1.4.2rc1" and "1.4" give "1.4.2rc1", "1.4" and "1.4"
+// give "1.4.0".
+func compatibleFrom(lo bound, p Version) (Version, bool) {
+	if lo.top || lo.v.epochNumeral().compare(p.epochNumeral()) != 0 {
+		return Version{}, false
+	}
+	x := p.release()
+	if i := len(p.Release); i < len(lo.v.Release) {
+		x.appendRelease(lo.v.releaseNumeral(i))
+	} else {
+		x.appendRelease(numeral{})
+	}
+	if lo.v.PreKind != "" {
+		x.setPre(lo.v.PreKind, lo.v.preNumeral())
+	}
+	if lo.v.HasPost {
+		x.setPost(lo.v.postNumeral())
+	}
+	if lo.v.HasDev {
+		x.setDev(lo.v.devNumeral())
+	}
+	return finish(x), compareGoNative(x, lo.v) == 0
+}
@@ -0,0 +1,243 @@
+package pyver
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSpecifierSetIsEmpty(t *testing.T) {
+	tests := []struct {
+		set    string
+		policy PreReleasePolicy
+		want   bool
+	}{
+		{">=1.2,<2,~=2.1", PreReleasesAuto, true},
+		{">=1.2,<2", PreReleasesAuto, false},
+		{"<1,>1", PreReleasesAuto, true},
+		{"<0", PreReleasesInclude, true},
+		{"", PreReleasesExclude, false},
+		// ">1.0" excludes the post-releases and local versions of 1.0, "<2"
+		// the pre-releases of 2.
+		{">1.0,<1.0.post1", PreReleasesInclude, true},
+		{">1.0,<=1.0.post1", PreReleasesInclude, true},
+		{">1.0.post1,<1.0.post2", PreReleasesInclude, true},
+		{">1.0.post1,<1.0.post3", PreReleasesInclude, false},
+		{"<2,>=2.0.dev0", PreReleasesInclude, true},
+		{"<2.0.post1,>=2.0.dev0", PreReleasesInclude, false},
+		{">1.0a1,<1.0", PreReleasesAuto, true},
+		{">1.0a1,<1.0a3", PreReleasesAuto, false},
+		{">1.0a1,<1.0a3", PreReleasesExclude, true},
+		{"==1.0.*,<1.0", PreReleasesInclude, true},
+		{"==1.*,!=1.0.*", PreReleasesAuto, false},
+		{"~=1.4.2,<1.4.2", PreReleasesAuto, true},
+		{"==1.0+abc,!=1.0+ABC", PreReleasesAuto, true},
+		{"==1.0,!=1.0+abc", PreReleasesAuto, false},
+		{">1.0,==1.0+abc", PreReleasesAuto, true},
+		{">=1.0,<=1.0,!=1.0", PreReleasesAuto, true},
+		{"~=v1.4", PreReleasesAuto, true},
+	}
+	for _, tc := range tests {
+		set := MustParseSpecifierSet(tc.set).WithPreReleases(tc.policy)
+		if got := set.IsEmpty(); got != tc.want {
+			t.Errorf("%q (%v).IsEmpty() = %v, want %v", tc.set, tc.policy, got, tc.want)
+		}
+	}
+}
+
+func TestSpecifierSetIsSubsetOf(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"~=1.4.2", ">=1.4,<1.5", true},
+		{">=1.4,<1.5", "~=1.4.2", false},
+		{"==1.4.*", ">=1.4,<1.5", true},
+		{"==1.0+abc", "==1.0", true},
+		{"==1.0", "==1.0+abc", false},
+		{">1.0", ">=1.0.post1", true},
+		{"<2", "<=1.99", false},
+		{"", ">=0", true},
+		{">=1.0a1", ">=1.0", false},
+		{">=1.0a1,<2", "<2", false},
+		{"<1.5,!=1.5", "<1.5", true},
+		// === matches a string, not every version equal to it.
+		{"==1.0.0+abc", "===1.0+abc", false},
+		{"==1.0", "===1.0", false},
+		{"===1.0+abc", "==1.0.0+abc", true},
+		{"===1.0,>=0.5", "===1.0", true},
+		{"===1.0", "===1.0,<2", true},
+		{"<0", "===1.0", true},
+	}
+	for _, tc := range tests {
+		a, b := MustParseSpecifierSet(tc.a), MustParseSpecifierSet(tc.b)
+		if got := a.IsSubsetOf(b); got != tc.want {
+			t.Errorf("%q.IsSubsetOf(%q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestSpecifierSetIntersect(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		want   string
+		policy PreReleasePolicy
+	}{
+		{">=1.2,<2", "~=1.5", "<2,>=1.2,~=1.5", PreReleasesAuto},
+		{">=1.0", ">=1.0.0,!=1.5", "!=1.5,>=1.0", PreReleasesAuto},
+		{">=1.0a1", "<2", "<2,>=1.0a1", PreReleasesExclude},
+		{">=1.0a1", "<2rc1", "<2rc1,>=1.0a1", PreReleasesAuto},
+		{"", "", "", PreReleasesAuto},
+	}
+	for _, tc := range tests {
+		got := MustParseSpecifierSet(tc.a).Intersect(MustParseSpecifierSet(tc.b))
+		if got.String() != tc.want || got.PreReleasePolicy() != tc.policy {
+			t.Errorf("%q.Intersect(%q) = %q (%v), want %q (%v)", tc.a, tc.b, got, got.PreReleasePolicy(), tc.want, tc.policy)
+		}
+	}
+	both := MustParseSpecifierSet("<2").WithPreReleases(PreReleasesInclude).Intersect(MustParseSpecifierSet(">=1").WithPreReleases(PreReleasesInclude))
+	if !both.Contains(MustParse("1.5a1")) {
+		t.Errorf("intersection of sets including pre-releases excludes 1.5a1: %q (%v)", both, both.PreReleasePolicy())
+	}
+}
+
+func TestSpecifierSetUnion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+		ok   bool
+	}{
+		{"~=1.4.2", "==1.5.*", "<1.6,>=1.4.2", true},
+		{"==1.4.*", "==1.5.*", "<1.6,>=1.4", true},
+		{"<1.0", "==1.0", "<=1.0", true},
+		{"<1", ">=2", "!=1.*", true},
+		{"==1.0", ">=1.0,<2", "<2,>=1.0", true},
+		{"==1.0", "==2.0", "", false},
+		// ">1.0" leaves out the post-releases of 1.0.
+		{"==1.0", ">1.0,<2", "", false},
+		{"===1.0", "==1.0", "==1.0", true},
+		{"===1.0", "==2.0", "", false},
+		{"==1.0.0", "===1.0", "==1.0.0", true},
+		{"<1.0", "===1.0", "", false},
+	}
+	for _, tc := range tests {
+		got, ok := MustParseSpecifierSet(tc.a).Union(MustParseSpecifierSet(tc.b))
+		if ok != tc.ok || ok && got.String() != tc.want {
+			t.Errorf("%q.Union(%q) = %q, %v, want %q, %v", tc.a, tc.b, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestSpecifierSetSimplify(t *testing.T) {
+	tests := []struct {
+		set    string
+		policy PreReleasePolicy
+		want   string
+		wantP  PreReleasePolicy
+	}{
+		{">=1.0,>=1.2,<3,<2", PreReleasesAuto, "~=1.2", PreReleasesAuto},
+		{">=1.4,<1.5", PreReleasesAuto, "==1.4.*", PreReleasesAuto},
+		{">=1.4.2,<1.5", PreReleasesAuto, "~=1.4.2", PreReleasesAuto},
+		{"~=1.4.2,>=1.4,<1.5,!=1.3", PreReleasesAuto, "~=1.4.2", PreReleasesAuto},
+		{"<1.5,!=1.5,!=2.0", PreReleasesAuto, "<1.5", PreReleasesAuto},
+		{"<2,<=1.9,!=3", PreReleasesAuto, "<=1.9", PreReleasesAuto},
+		{"==1.0,>=0.5", PreReleasesAuto, "==1.0", PreReleasesAuto},
+		{">=1.2,<2,~=2.1", PreReleasesAuto, "<0", PreReleasesAuto},
+		{">=1.0a1,<2", PreReleasesAuto, "~=1.0a1", PreReleasesAuto},
+		{">=1.0a1,<2", PreReleasesExclude, "==1.*", PreReleasesAuto},
+		{">=1.4,<1.5", PreReleasesInclude, "~=1.4.0", PreReleasesInclude},
+		{">1.0a1", PreReleasesAuto, ">1.0a1", PreReleasesAuto},
+		{"!=1.0+abc,>=1,<2", PreReleasesAuto, "!=1+abc,==1.*", PreReleasesAuto},
+		{"", PreReleasesAuto, "", PreReleasesAuto},
+		{">=1.0", PreReleasesAuto, ">=1.0", PreReleasesAuto},
+		{">=1.0,<3.0", PreReleasesAuto, "<3.0,>=1.0", PreReleasesAuto},
+		// An empty set would fall back to pre-releases in Filter.
+		{">=0", PreReleasesAuto, "", PreReleasesExclude},
+		{"===foo,>=1", PreReleasesAuto, "===foo", PreReleasesAuto},
+		{"===1.0,==1.0", PreReleasesAuto, "===1.0", PreReleasesAuto},
+	}
+	for _, tc := range tests {
+		got := MustParseSpecifierSet(tc.set).WithPreReleases(tc.policy).Simplify()
+		if got.String() != tc.want || got.PreReleasePolicy() != tc.wantP {
+			t.Errorf("%q (%v).Simplify() = %q (%v), want %q (%v)", tc.set, tc.policy, got, got.PreReleasePolicy(), tc.want, tc.wantP)
+		}
+	}
+}
+
+// regionVersions returns versions at and next to the bounds of the regions
+// of specifierInputs.
+func regionVersions() []Version {
+	vs := parseAll(orderedVersions)
+	seen := map[string]bool{}
+	for _, s := range specifierInputs() {
+		sp, err := ParseSpecifier(s)
+		if err != nil || sp.op == OpArbitrary {
+			continue
+		}
+		v := sp.v.WithoutLocal()
+		l, _ := v.WithLocal("xyz")
+		for _, w := range []Version{sp.v, v, l, firstDev(v), finish(v.release()), v.NextPost(), v.NextDev(), v.BumpRelease(len(v.Release))} {
+			if !seen[w.String()] {
+				seen[w.String()] = true
+				vs = append(vs, w)
+			}
+		}
+	}
+	return vs
+}
+
+func randomSpecifierSet(r *rand.Rand, clauses []string) SpecifierSet {
+	var b strings.Builder
+	for i := range r.Intn(4) {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(clauses[r.Intn(len(clauses))])
+	}
+	return MustParseSpecifierSet(b.String()).WithPreReleases(PreReleasePolicy(r.Intn(3)))
+}
+
+// TestSpecifierSetAlgebra checks the set operations against Contains on
+// random sets, and that the region of a set holds exactly the versions it
+// contains.
+func TestSpecifierSetAlgebra(t *testing.T) {
+	var clauses []string
+	for _, s := range specifierInputs() {
+		if sp, err := ParseSpecifier(s); err == nil && sp.op != OpArbitrary {
+			clauses = append(clauses, s)
+		}
+	}
+	vs := regionVersions()
+	r := rand.New(rand.NewSource(1))
+	for range 500 {
+		a, b := randomSpecifierSet(r, clauses), randomSpecifierSet(r, clauses)
+		ra, empty := a.region(), a.IsEmpty()
+		if w, ok := ra.witness(); ok == empty || ok && !a.Contains(w) {
+			t.Fatalf("%q (%v): witness %q, %v is not contained", a, a.policy, w, ok)
+		}
+		if w, ok := ra.minus(b.region()).witness(); ok == a.IsSubsetOf(b) || ok && b.Contains(w) {
+			t.Fatalf("%q.IsSubsetOf(%q) = %v, but witness %q, %v", a, b, a.IsSubsetOf(b), w, ok)
+		}
+		simple := a.Simplify()
+		if simple.Len() > a.Len() {
+			t.Errorf("%q (%v).Simplify() = %q, which is longer", a, a.policy, simple)
+		}
+		i := a.Intersect(b)
+		u, uok := a.Union(b)
+		for _, v := range vs {
+			in, inB := a.Contains(v), b.Contains(v)
+			switch {
+			case ra.contains(v) != in:
+				t.Fatalf("%q (%v): region contains %q = %v, Contains = %v", a, a.policy, v, ra.contains(v), in)
+			case in && empty:
+				t.Fatalf("%q (%v).IsEmpty(), but it contains %q", a, a.policy, v)
+			case simple.Contains(v) != in:
+				t.Fatalf("%q (%v).Simplify() = %q (%v), which differs on %q", a, a.policy, simple, simple.policy, v)
+			case i.Contains(v) != (in && inB):
+				t.Fatalf("%q.Intersect(%q) = %q (%v), which differs on %q", a, b, i, i.policy, v)
+			case uok && u.Contains(v) != (in || inB):
+				t.Fatalf("%q.Union(%q) = %q (%v), which differs on %q", a, b, u, u.policy, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package pyver
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// Backend implements version parsing and comparison. NativeBackend and
+// *Worker, which delegates to Python's packaging library, implement it.
+type Backend interface {
+	Parse(s string) (Version, error)
+	Compare(v1, v2 Version) (int, error)
+}
+
+// ContextBackend is implemented by backends whose calls can be abandoned
+// when a context is done. ParseContext and CompareContext use these methods
+// when the backend has them, and the plain ones otherwise.
+type ContextBackend interface {
+	Backend
+	ParseContext(ctx context.Context, s string) (Version, error)
+	CompareContext(ctx context.Context, v1, v2 Version) (int, error)
+}
+
+// stringSorter is implemented by backends that sort version strings in a
+// single operation.
+type stringSorter interface {
+	SortStringsContext(ctx context.Context, ss []string) error
+}
+
+// loggingBackend is implemented by backends that can report the
+// diagnostics of a call to the logger of the Parser making it.
+type loggingBackend interface {
+	parseLogged(ctx context.Context, log *slog.Logger, s string) (Version, error)
+	compareLogged(ctx context.Context, log *slog.Logger, v1, v2 Version) (int, error)
+}
+
+var (
+	_ Backend        = NativeBackend{}
+	_ ContextBackend = (*Worker)(nil)
+	_ stringSorter   = (*Worker)(nil)
+	_ loggingBackend = (*Worker)(nil)
+)
+
+// NativeBackend is the Go-native implementation. It needs no Python
+// interpreter and never fails to compare.
+type NativeBackend struct{}
+
+// Parse parses s.
+func (NativeBackend) Parse(s string) (Version, error) {
+	return parseGoNative(s)
+}
+
+// Compare compares two versions.
+func (NativeBackend) Compare(v1, v2 Version) (int, error) {
+	return compareGoNative(v1, v2), nil
+}
+
+// UseGoNative selects the backend of the package-level functions when no
+// backend was installed with SetDefaultBackend: NativeBackend if true, the
+// shared Python Worker if false. It is initialized from the USE_GO_NATIVE
+// environment variable ("0" or "false" select Python) and defaults to true.
+//
+// Deprecated: Changing UseGoNative while other goroutines parse is a data
+// race. Use SetDefaultBackend, or a Parser with its Backend field set.
+var UseGoNative = useGoNativeFromEnv()
+
+func useGoNativeFromEnv() bool {
+	native, err := strconv.ParseBool(os.Getenv("USE_GO_NATIVE"))
+	return native || err != nil
+}
+
+var installedBackend atomic.Pointer[Backend]
+
+// SetDefaultBackend installs b as the backend of the package-level
+// functions and of Parsers without a Backend. It is safe to call
+// concurrently with parsing. Passing nil restores the selection made by
+// USE_GO_NATIVE.
+func SetDefaultBackend(b Backend) {
+	if b == nil {
+		installedBackend.Store(nil)
+		return
+	}
+	installedBackend.Store(&b)
+}
+
+// DefaultBackend returns the backend used by the package-level functions.
+func DefaultBackend() Backend {
+	if b := installedBackend.Load(); b != nil {
+		return *b
+	}
+	if !UseGoNative {
+		return defaultWorker
+	}
+	return NativeBackend{}
+}
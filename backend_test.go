@@ -1,6 +1,8 @@
 package pyver
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -24,12 +26,159 @@ func TestBackendErrorHandling(t *testing.T) {
 }
 
 func TestBackendMissingScript(t *testing.T) {
-	// Simulate missing backend script
+	// A BackendPath override that does not exist is reported when the
+	// backend is used, not at import time.
+	useBackend(t, defaultWorker)
 	orig := BackendPath
 	BackendPath = "nonexistent_pyver_backend.py"
 	defer func() { BackendPath = orig }()
-	_, err := Parse("1.2.3")
-	if err == nil {
-		t.Skip("skipping: would break suite if backend is missing, but got no error")
+	if _, err := Parse("1.2.3"); !errors.Is(err, ErrBackend) {
+		t.Errorf("Parse with a missing script: got %v, want ErrBackend", err)
+	}
+}
+
+func TestBackendEmbeddedScript(t *testing.T) {
+	if BackendPath != "" {
+		t.Fatalf("BackendPath = %q, want the embedded script by default", BackendPath)
+	}
+	requireBackend(t)
+	// The embedded script does not depend on the working directory.
+	t.Chdir(t.TempDir())
+	v, err := Parse("1.0-RC1")
+	if err != nil || v.Normalized != "1.0rc1" {
+		t.Errorf("Parse outside the module = %q, %v, want 1.0rc1", v.Normalized, err)
+	}
+}
+
+// useBackend installs b as the default backend for the duration of the test.
+func useBackend(t *testing.T, b Backend) {
+	t.Helper()
+	SetDefaultBackend(b)
+	t.Cleanup(func() { SetDefaultBackend(nil) })
+}
+
+// requireBackend switches to the Python backend for the duration of the test,
+// skipping it when the interpreter or the packaging library is unavailable.
+func requireBackend(t *testing.T) {
+	t.Helper()
+	useBackend(t, defaultWorker)
+	if _, err := Parse("1.0"); err != nil {
+		t.Skipf("python backend unavailable: %v", err)
+	}
+}
+
+func TestBackendParityZeroSegments(t *testing.T) {
+	cases := []string{
+		"1.0", "1.0a0", "1.0a", "1.0rc12", "1.0.post0", "1.0.post", "1.0-0",
+		"1.0.dev0", "1.0dev", "1.0.post0.dev0", "1.0a0.post0.dev0", "1.0a0.dev0",
+		"1.0a1.dev1", "1.0a1.post1", "1.0.post1.dev1", "1.0.post1",
+		"1.20240101123045123456789", "1.0.post99999999999999999999", "1.0+007",
+	}
+	native := make([]Version, len(cases))
+	for i, s := range cases {
+		v, err := parseGoNative(s)
+		if err != nil {
+			t.Fatalf("parseGoNative(%q): %v", s, err)
+		}
+		native[i] = v
+	}
+	requireBackend(t)
+	for i, s := range cases {
+		want := native[i]
+		got, err := Parse(s)
+		if err != nil {
+			t.Fatalf("backend Parse(%q): %v", s, err)
+		}
+		if got.Normalized != want.Normalized {
+			t.Errorf("%q: normalized: backend %q, native %q", s, got.Normalized, want.Normalized)
+		}
+		if got.PreKind != want.PreKind || got.PreNum != want.PreNum ||
+			got.HasPost != want.HasPost || got.PostNum != want.PostNum ||
+			got.HasDev != want.HasDev || got.DevNum != want.DevNum {
+			t.Errorf("%q: fields: backend %+v, native %+v", s, got, want)
+		}
+	}
+	for i := range cases {
+		for j := range cases {
+			want := compareGoNative(native[i], native[j])
+			if got := Compare(native[i], native[j]); got != want {
+				t.Errorf("Compare(%q, %q): backend %d, native %d", cases[i], cases[j], got, want)
+			}
+		}
+	}
+}
+
+func TestBackendParseErrorParity(t *testing.T) {
+	cases := []string{"", "1..0", "1.0.0a1a2", "1.0.0+abc+def", "1.0.dev1.post1"}
+	native := make([]error, len(cases))
+	for i, s := range cases {
+		_, native[i] = parseGoNative(s)
+	}
+	requireBackend(t)
+	for i, s := range cases {
+		_, err := Parse(s)
+		var got, want *ParseError
+		if !errors.As(err, &got) || !errors.As(native[i], &want) {
+			t.Fatalf("%q: expected *ParseError from both modes, got backend %v, native %v", s, err, native[i])
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: backend %+v, native %+v", s, *got, *want)
+		}
+	}
+}
+
+func TestBackendAccessors(t *testing.T) {
+	requireBackend(t)
+	// parseBackend fails if packaging disagrees with any accessor
+	for _, s := range []string{"1.2.3", "1!1.2.3dev1+abc", "1.2.3rc1.post2", "1.0.post1.dev0", "2", "1.20240101123045123456789", "1.0.0+Abc.007", "0.0", "2!1.0.0.post0.dev0"} {
+		if _, err := Parse(s); err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+		}
+	}
+}
+
+func TestBackendTryCompare(t *testing.T) {
+	useBackend(t, defaultWorker)
+	orig := BackendPath
+	BackendPath = "nonexistent_pyver_backend.py"
+	defer func() { BackendPath = orig }()
+
+	v1, v2 := Version{Original: "1.0"}, Version{Original: "2.0"}
+	if _, err := TryCompare(v1, v2); !errors.Is(err, ErrBackend) {
+		t.Errorf("TryCompare with a missing script: got %v, want ErrBackend", err)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Compare with a missing script did not panic")
+		}
+	}()
+	Compare(v1, v2)
+}
+
+func TestBackendSelection(t *testing.T) {
+	for env, native := range map[string]bool{"": true, "1": true, "true": true, "0": false, "false": false, "FALSE": false, "junk": true} {
+		t.Setenv("USE_GO_NATIVE", env)
+		if got := useGoNativeFromEnv(); got != native {
+			t.Errorf("USE_GO_NATIVE=%q: native = %v, want %v", env, got, native)
+		}
+	}
+
+	useBackend(t, NativeBackend{})
+	if _, ok := DefaultBackend().(NativeBackend); !ok {
+		t.Errorf("DefaultBackend() = %T after SetDefaultBackend(NativeBackend{})", DefaultBackend())
+	}
+
+	// A Parser bound to a backend ignores the default.
+	orig := BackendPath
+	BackendPath = "nonexistent_pyver_backend.py"
+	defer func() { BackendPath = orig }()
+	w := &Worker{}
+	defer w.Close()
+	p := &Parser{Backend: w}
+	if _, err := p.Parse("1.0"); !errors.Is(err, ErrBackend) {
+		t.Errorf("Parser bound to a broken worker: got %v, want ErrBackend", err)
+	}
+	if _, err := Parse("1.0"); err != nil {
+		t.Errorf("package Parse with the native default: %v", err)
 	}
 }
@@ -0,0 +1,242 @@
+package pyver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidBump is returned when a derivation cannot produce a version that
+// sorts after the one it starts from, or is given an unknown argument.
+var ErrInvalidBump = errors.New("invalid version bump")
+
+// The derivation methods below never modify v; each returns a fresh Version
+// whose Original and Normalized hold the normalized string. They follow
+// these rules:
+//
+//   - A bump of a release component increments it, sets the components
+//     after it to zero and drops the pre-, post- and dev-release segments.
+//   - A new pre-, post- or dev-release segment starts at 0.
+//   - Every derivation except WithEpoch and WithLocal drops the local label,
+//     as a local label belongs to one particular build.
+//   - NextPre, NextPost and NextDev always return a version that sorts
+//     after v.
+
+// BumpMajor is BumpRelease(0): "1.2.3rc1" -> "2.0.0".
+func (v Version) BumpMajor() Version {
+	return v.BumpRelease(0)
+}
+
+// BumpMinor is BumpRelease(1): "1.2.3rc1" -> "1.3.0".
+func (v Version) BumpMinor() Version {
+	return v.BumpRelease(1)
+}
+
+// BumpMicro is BumpRelease(2): "1.2.3rc1" -> "1.2.4".
+func (v Version) BumpMicro() Version {
+	return v.BumpRelease(2)
+}
+
+// BumpRelease increments the release component at index i, zeroing the
+// ones after it and dropping every other segment except the epoch. The
+// release is padded with zeros if it has fewer than i+1 components:
+// "1" with i == 2 -> "1.0.1". BumpRelease panics if i is negative.
+func (v Version) BumpRelease(i int) Version {
+	if i < 0 {
+		panic("pyver: negative release index")
+	}
+	w := Version{}
+	w.setEpoch(v.epochNumeral())
+	for j := range max(len(v.Release), i+1) {
+		switch {
+		case j < i && j < len(v.Release):
+			w.appendRelease(v.releaseNumeral(j))
+		case j == i && j < len(v.Release):
+			w.appendRelease(v.releaseNumeral(j).inc())
+		case j == i:
+			w.appendRelease(numeral{n: 1})
+		default:
+			w.appendRelease(numeral{})
+		}
+	}
+	return finish(w)
+}
+
+// NextPre returns the next pre-release of the given kind, which may be any
+// spelling Parse accepts ("a", "alpha", "b", "beta", "rc", "c", "pre",
+// "preview"):
+//
+//	1.0a1 -> NextPre("a") -> 1.0a2
+//	1.0a1 -> NextPre("rc") -> 1.0rc0
+//	1.0a1.dev2 -> NextPre("a") -> 1.0a1
+//	1.0.dev2 -> NextPre("b") -> 1.0b0
+//	1.0 -> NextPre("rc") -> 1.1rc0
+//
+// A final or post-release starts a pre-release of the next release, bumping
+// the last release component. Moving to an earlier kind, as from "rc" to
+// "a", fails with ErrInvalidBump.
+func (v Version) NextPre(kind string) (Version, error) {
+	k, ok := preKindOf(kind)
+	if !ok {
+		return Version{}, fmt.Errorf("%w: unknown pre-release kind %q", ErrInvalidBump, kind)
+	}
+	switch {
+	case v.PreKind == "" && !v.HasPost && v.HasDev:
+		// A dev release leading up to this release.
+		w := v.release()
+		w.setPre(k, numeral{})
+		return finish(w), nil
+	case v.PreKind == "":
+		w := v.BumpRelease(max(len(v.Release)-1, 0))
+		w.setPre(k, numeral{})
+		return finish(w), nil
+	case preKindOrder[k] < preKindOrder[v.PreKind]:
+		return Version{}, fmt.Errorf("%w: %s cannot be followed by %s", ErrInvalidBump, v.PreKind, k)
+	}
+	w := v.release()
+	switch {
+	case k != v.PreKind:
+		w.setPre(k, numeral{})
+	case v.HasDev && !v.HasPost:
+		w.setPre(k, v.preNumeral())
+	default:
+		w.setPre(k, v.preNumeral().inc())
+	}
+	return finish(w), nil
+}
+
+// NextPost returns the next post-release, keeping any pre-release segment:
+// "1.0" -> "1.0.post0", "1.0rc1.post1" -> "1.0rc1.post2". A dev release of
+// a post-release becomes that post-release: "1.0.post2.dev0" -> "1.0.post2".
+func (v Version) NextPost() Version {
+	w := v.release()
+	if v.PreKind != "" {
+		w.setPre(v.PreKind, v.preNumeral())
+	}
+	switch {
+	case v.HasPost && v.HasDev:
+		w.setPost(v.postNumeral())
+	case v.HasPost:
+		w.setPost(v.postNumeral().inc())
+	default:
+		w.setPost(numeral{})
+	}
+	return finish(w)
+}
+
+// NextDev returns the next development release. An existing dev segment is
+// incremented; otherwise a dev release of the next release is started:
+//
+//	1.0.dev1 -> 1.0.dev2
+//	1.0a1 -> 1.0a2.dev0
+//	1.0.post1 -> 1.0.post2.dev0
+//	1.0 -> 1.1.dev0
+func (v Version) NextDev() Version {
+	var w Version
+	switch {
+	case v.HasDev:
+		w = v.WithoutLocal()
+		w.setDev(v.devNumeral().inc())
+	case v.HasPost:
+		w = v.NextPost()
+		w.setDev(numeral{})
+	case v.PreKind != "":
+		w, _ = v.NextPre(v.PreKind)
+		w.setDev(numeral{})
+	default:
+		w = v.BumpRelease(max(len(v.Release)-1, 0))
+		w.setDev(numeral{})
+	}
+	return finish(w)
+}
+
+// Finalize returns the final release v leads up to or belongs to. A
+// pre-release loses its pre-, post- and dev-release segments, while a dev
+// release of a post-release becomes that post-release:
+//
+//	1.0rc1.post1 -> 1.0
+//	1.0.dev3 -> 1.0
+//	1.0.post1.dev0 -> 1.0.post1
+func (v Version) Finalize() Version {
+	w := v.release()
+	if v.PreKind == "" && v.HasPost {
+		w.setPost(v.postNumeral())
+	}
+	return finish(w)
+}
+
+// WithLocal returns v with its local label replaced by label, which is
+// validated and normalized like the part of a version after '+':
+// "1.0" -> WithLocal("Ubuntu-1") -> "1.0+ubuntu.1".
+func (v Version) WithLocal(label string) (Version, error) {
+	l, err := parseGoNative("0+" + label)
+	if err != nil {
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			pe.Input, pe.Offset = label, max(pe.Offset-2, 0)
+		}
+		return Version{}, err
+	}
+	w := v.WithoutLocal()
+	w.Local = l.Local
+	return finish(w), nil
+}
+
+// WithoutLocal returns v without its local label: "1.0+abc" -> "1.0".
+func (v Version) WithoutLocal() Version {
+	w := v.release()
+	if v.PreKind != "" {
+		w.setPre(v.PreKind, v.preNumeral())
+	}
+	if v.HasPost {
+		w.setPost(v.postNumeral())
+	}
+	if v.HasDev {
+		w.setDev(v.devNumeral())
+	}
+	return finish(w)
+}
+
+// WithEpoch returns v with its epoch set to n, keeping every other segment.
+// It panics if n is negative.
+func (v Version) WithEpoch(n int) Version {
+	if n < 0 {
+		panic("pyver: negative epoch")
+	}
+	w := v.WithoutLocal()
+	w.Epoch = n
+	if w.wide != nil {
+		w.wide.epoch = ""
+	}
+	w.Local = slices.Clone(v.Local)
+	return finish(w)
+}
+
+// release returns a new Version holding a copy of v's epoch and release.
+func (v Version) release() Version {
+	var w Version
+	w.setEpoch(v.epochNumeral())
+	w.Release = make([]int, 0, len(v.Release))
+	for i := range v.Release {
+		w.appendRelease(v.releaseNumeral(i))
+	}
+	return w
+}
+
+// finish recomputes the normalized string of a derived Version.
+func finish(v Version) Version {
+	v.Normalized = versionToString(v)
+	v.Original = v.Normalized
+	return v
+}
+
+// preKindOf returns the normalized pre-release kind for a spelling.
+func preKindOf(s string) (string, bool) {
+	for _, k := range suffixKeywords {
+		if k.seg == SegmentPre && strings.EqualFold(s, k.word) {
+			return k.kind, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,125 @@
+package pyver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		input string
+		bump  func(Version) Version
+		want  string
+	}{
+		{"1.2.3", Version.BumpMajor, "2.0.0"},
+		{"1.2.3rc1.post1.dev2+abc", Version.BumpMajor, "2.0.0"},
+		{"1!1.2.3", Version.BumpMinor, "1!1.3.0"},
+		{"1.2.3.4", Version.BumpMinor, "1.3.0.0"},
+		{"1.2.3a1", Version.BumpMicro, "1.2.4"},
+		{"1", Version.BumpMicro, "1.0.1"},
+		{"1.9223372036854775807", Version.BumpMinor, "1.9223372036854775808"},
+		{"1.99999999999999999999", Version.BumpMinor, "1.100000000000000000000"},
+		{"1.0", func(v Version) Version { return v.BumpRelease(3) }, "1.0.0.1"},
+
+		{"1.0", Version.NextPost, "1.0.post0"},
+		{"1.0.post1", Version.NextPost, "1.0.post2"},
+		{"1.0rc1", Version.NextPost, "1.0rc1.post0"},
+		{"1.0.post2.dev0+abc", Version.NextPost, "1.0.post2"},
+		{"1.0.dev3", Version.NextPost, "1.0.post0"},
+
+		{"1.0.dev1", Version.NextDev, "1.0.dev2"},
+		{"1.0a1.post1.dev1+abc", Version.NextDev, "1.0a1.post1.dev2"},
+		{"1.0a1", Version.NextDev, "1.0a2.dev0"},
+		{"1.0.post1", Version.NextDev, "1.0.post2.dev0"},
+		{"1.0", Version.NextDev, "1.1.dev0"},
+
+		{"1.0rc1.post1", Version.Finalize, "1.0"},
+		{"1.0.dev3", Version.Finalize, "1.0"},
+		{"1.0.post1.dev0", Version.Finalize, "1.0.post1"},
+		{"1.0+abc", Version.Finalize, "1.0"},
+
+		{"1.0rc1+abc", Version.WithoutLocal, "1.0rc1"},
+		{"1.0rc1+abc", func(v Version) Version { return v.WithEpoch(2) }, "2!1.0rc1+abc"},
+		{"3!1.0", func(v Version) Version { return v.WithEpoch(0) }, "1.0"},
+	}
+	for _, tc := range tests {
+		v := MustParse(tc.input)
+		got := tc.bump(v)
+		if got.Normalized != tc.want || got.Original != tc.want {
+			t.Errorf("%s: got %q (original %q), want %q", tc.input, got.Normalized, got.Original, tc.want)
+		}
+		if got.String() != MustParse(tc.want).String() || Compare(got, MustParse(tc.want)) != 0 {
+			t.Errorf("%s: result %+v differs from Parse(%q)", tc.input, got, tc.want)
+		}
+		if v.String() != MustParse(tc.input).String() {
+			t.Errorf("%s: input modified to %q", tc.input, v)
+		}
+	}
+}
+
+func TestNextPre(t *testing.T) {
+	tests := []struct {
+		input, kind, want string
+	}{
+		{"1.0a1", "a", "1.0a2"},
+		{"1.0a1", "rc", "1.0rc0"},
+		{"1.0a1", "Beta", "1.0b0"},
+		{"1.0a1.dev2", "a", "1.0a1"},
+		{"1.0a1.post1", "a", "1.0a2"},
+		{"1.0.dev2", "b", "1.0b0"},
+		{"1.0", "rc", "1.1rc0"},
+		{"1.0.post1", "preview", "1.1rc0"},
+		{"1.0rc1+abc", "c", "1.0rc2"},
+	}
+	for _, tc := range tests {
+		v := MustParse(tc.input)
+		got, err := v.NextPre(tc.kind)
+		if err != nil {
+			t.Errorf("%s.NextPre(%q): %v", tc.input, tc.kind, err)
+			continue
+		}
+		if got.Normalized != tc.want {
+			t.Errorf("%s.NextPre(%q) = %q, want %q", tc.input, tc.kind, got, tc.want)
+		}
+		if Compare(got, v) <= 0 {
+			t.Errorf("%s.NextPre(%q) = %q does not sort after the input", tc.input, tc.kind, got)
+		}
+	}
+
+	for _, tc := range []struct{ input, kind string }{{"1.0rc1", "a"}, {"1.0b1", "alpha"}, {"1.0", "gamma"}, {"1.0", ""}} {
+		if _, err := MustParse(tc.input).NextPre(tc.kind); !errors.Is(err, ErrInvalidBump) {
+			t.Errorf("%s.NextPre(%q) = %v, want ErrInvalidBump", tc.input, tc.kind, err)
+		}
+	}
+}
+
+func TestNextAlwaysIncreases(t *testing.T) {
+	for _, s := range orderedVersions {
+		v := MustParse(s)
+		for name, next := range map[string]Version{"NextPost": v.NextPost(), "NextDev": v.NextDev()} {
+			if Compare(next, v) <= 0 {
+				t.Errorf("%s.%s() = %q does not sort after the input", s, name, next)
+			}
+		}
+	}
+}
+
+func TestWithLocal(t *testing.T) {
+	v := MustParse("1.0+old")
+	got, err := v.WithLocal("Ubuntu-01_b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Normalized != "1.0+ubuntu.1.b" {
+		t.Errorf("WithLocal = %q, want %q", got, "1.0+ubuntu.1.b")
+	}
+
+	_, err = v.WithLocal("abc..def")
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Input != "abc..def" || pe.Offset != 4 || pe.Segment != SegmentLocal {
+		t.Errorf("WithLocal(invalid) = %v, want a ParseError at offset 4 of the label", err)
+	}
+	if _, err := v.WithLocal(""); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("WithLocal(\"\") = %v, want ErrInvalidVersion", err)
+	}
+}
@@ -0,0 +1,58 @@
+package pyver
+
+// CanonicalOption adjusts the form produced by Canonical and
+// CanonicalizeString.
+type CanonicalOption func(*canonicalOptions)
+
+type canonicalOptions struct {
+	keepTrailingZeros bool
+	stripLocal        bool
+}
+
+// KeepTrailingZeros keeps trailing zero release components, like
+// canonicalize_version(..., strip_trailing_zero=False): "1.0.0" stays
+// "1.0.0".
+func KeepTrailingZeros() CanonicalOption {
+	return func(o *canonicalOptions) { o.keepTrailingZeros = true }
+}
+
+// StripLocal removes the local version label: "1.0+abc" -> "1".
+func StripLocal() CanonicalOption {
+	return func(o *canonicalOptions) { o.stripLocal = true }
+}
+
+// Canonical returns the canonical form of v as produced by packaging's
+// canonicalize_version: the normalized string with trailing zero release
+// components removed, keeping at least one ("1.0.0" -> "1", "0.0" -> "0").
+// Per PEP 625 this is the form used in index and cache keys.
+func (v Version) Canonical(opts ...CanonicalOption) string {
+	var o canonicalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.keepTrailingZeros {
+		v.Release = v.Release[:min(max(releaseLen(v), 1), len(v.Release))]
+	}
+	if o.stripLocal {
+		v.Local = nil
+	}
+	return versionToString(v)
+}
+
+// CanonicalizeString parses s and returns its canonical form, like
+// packaging.utils.canonicalize_version. As there, a string that is not a
+// valid version is returned unaltered.
+func CanonicalizeString(s string, opts ...CanonicalOption) string {
+	v, err := Parse(s)
+	if err != nil {
+		return s
+	}
+	return v.Canonical(opts...)
+}
+
+// HashKey returns a string that is equal for two versions exactly when
+// Compare reports them equal, so "1.0" and "1.0.0" share a key. Unlike
+// Version itself, it can be used as a Go map key.
+func (v Version) HashKey() string {
+	return v.Canonical()
+}
@@ -0,0 +1,57 @@
+package pyver
+
+import "testing"
+
+func TestCanonicalizeString(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  []CanonicalOption
+		want  string
+	}{
+		// Expected values from packaging.utils.canonicalize_version
+		{"1.0.0", nil, "1"},
+		{"1.0.0+Abc.007", nil, "1+abc.7"},
+		{"0.0.0", nil, "0"},
+		{"1.0.1.0", nil, "1.0.1"},
+		{"1.0a0", nil, "1a0"},
+		{"2!1.0.0.post0.dev0", nil, "2!1.post0.dev0"},
+		{"1.0.0 x", nil, "1.0.0 x"},
+		{"1.0.0", []CanonicalOption{KeepTrailingZeros()}, "1.0.0"},
+		{"v1.0-rc1", []CanonicalOption{KeepTrailingZeros()}, "1.0rc1"},
+		// pyver extensions
+		{"1.0.0+abc", []CanonicalOption{StripLocal()}, "1"},
+		{"1.0.0+abc", []CanonicalOption{StripLocal(), KeepTrailingZeros()}, "1.0.0"},
+	}
+	for _, tc := range tests {
+		if got := CanonicalizeString(tc.input, tc.opts...); got != tc.want {
+			t.Errorf("CanonicalizeString(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+	if got := (Version{}).Canonical(); got != "" {
+		t.Errorf("zero Version Canonical() = %q, want \"\"", got)
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	var vs []Version
+	for _, s := range referenceInputs() {
+		if v, err := Parse(s); err == nil {
+			vs = append(vs, v)
+		}
+	}
+	for _, a := range vs {
+		for _, b := range vs {
+			if eq := a.HashKey() == b.HashKey(); eq != (Compare(a, b) == 0) {
+				t.Errorf("HashKey(%q) == HashKey(%q) is %v, Compare is %d", a.Original, b.Original, eq, Compare(a, b))
+			}
+		}
+	}
+
+	seen := map[string]string{}
+	for _, s := range []string{"1.0", "1.0.0", "1"} {
+		seen[MustParse(s).HashKey()] = s
+	}
+	if len(seen) != 1 {
+		t.Errorf("1.0, 1.0.0 and 1 produced %d map keys, want 1", len(seen))
+	}
+}
@@ -4,54 +4,72 @@ import (
 	"testing"
 )
 
-func TestCompare(t *testing.T) {
-	tests := []struct {
-		name   string
-		v1     string
-		v2     string
-		expect int // -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
-	}{
-		{"numeric lt", "1.2.3", "1.2.4", -1},
-		{"numeric eq", "1.2.3", "1.2.3", 0},
-		{"numeric gt", "1.2.4", "1.2.3", 1},
-		// Pre-releases
-		{"pre-release a<b", "1.0.0a1", "1.0.0b1", -1},
-		{"pre-release b<rc", "1.0.0b1", "1.0.0rc1", -1},
-		{"pre-release rc<final", "1.0.0rc1", "1.0.0", -1},
-		// Post-releases
-		{"post-release", "1.0.0", "1.0.0.post1", -1},
-		// Dev releases
-		{"dev<a", "1.0.0.dev1", "1.0.0a1", -1},
-		// Epochs
-		{"epoch", "1!1.0.0", "1.0.0", 1},
-		// Local versions (should be equal for ordering)
-		{"local eq", "1.0.0+abc", "1.0.0+xyz", -1},
-		// Local version tie-breakers
-		{"local tie-breaker numeric", "1.0.0+1", "1.0.0+2", -1},
-		{"local tie-breaker lexicographic", "1.0.0+abc", "1.0.0+abd", -1},
-		{"local tie-breaker mixed", "1.0.0+1.abc", "1.0.0+1.abd", -1},
-		{"local vs no local", "1.0.0", "1.0.0+abc", -1},      // PEP 440: local only matters if public is equal, but local wins
-		{"local numeric vs string", "1.0.0+1", "1.0.0+a", 1}, // numeric < string in local, so 1 > a
-		// Normalization and segment equivalence
-		{"normalization 1.0 vs 1.0.0", "1.0", "1.0.0", 0},
-		{"normalization 1.0.0 vs 1.0.0.0", "1.0.0", "1.0.0.0", 0},
-		// Real-world messy versions
-		{"messy rc dash", "1.0.0-rc1", "1.0.0rc1", 0}, // Should normalize
-		{"messy post dash", "1.0.0-post1", "1.0.0.post1", 0},
-		{"messy dev dash", "1.0.0-dev1", "1.0.0.dev1", 0},
-		// Pre-release vs dev/post
-		{"pre vs dev", "1.0.0a1", "1.0.0.dev1", 1},
-		{"pre vs post", "1.0.0a1", "1.0.0.post1", -1},
-		// Epoch with pre/post/dev
-		{"epoch with pre", "1!1.0.0a1", "1.0.0a1", 1},
-		{"epoch with post", "1!1.0.0.post1", "1.0.0.post1", 1},
-		// Leading zeros (should normalize)
-		{"leading zeros", "1.02.3", "1.2.3", 0},
-		// Complex real-world
-		{"complex dev/post", "1.0.0.post1.dev2", "1.0.0.post1.dev3", -1},
-	}
+// compareCases pair two versions with the expected sign of Compare.
+var compareCases = []struct {
+	name   string
+	v1     string
+	v2     string
+	expect int // -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
+}{
+	{"numeric lt", "1.2.3", "1.2.4", -1},
+	{"numeric eq", "1.2.3", "1.2.3", 0},
+	{"numeric gt", "1.2.4", "1.2.3", 1},
+	// Pre-releases
+	{"pre-release a<b", "1.0.0a1", "1.0.0b1", -1},
+	{"pre-release b<rc", "1.0.0b1", "1.0.0rc1", -1},
+	{"pre-release rc<final", "1.0.0rc1", "1.0.0", -1},
+	// Post-releases
+	{"post-release", "1.0.0", "1.0.0.post1", -1},
+	// Dev releases
+	{"dev<a", "1.0.0.dev1", "1.0.0a1", -1},
+	// Epochs
+	{"epoch", "1!1.0.0", "1.0.0", 1},
+	// Local versions (should be equal for ordering)
+	{"local eq", "1.0.0+abc", "1.0.0+xyz", -1},
+	// Local version tie-breakers
+	{"local tie-breaker numeric", "1.0.0+1", "1.0.0+2", -1},
+	{"local tie-breaker lexicographic", "1.0.0+abc", "1.0.0+abd", -1},
+	{"local tie-breaker mixed", "1.0.0+1.abc", "1.0.0+1.abd", -1},
+	{"local vs no local", "1.0.0", "1.0.0+abc", -1},      // PEP 440: local only matters if public is equal, but local wins
+	{"local numeric vs string", "1.0.0+1", "1.0.0+a", 1}, // numeric < string in local, so 1 > a
+	// Normalization and segment equivalence
+	{"normalization 1.0 vs 1.0.0", "1.0", "1.0.0", 0},
+	{"normalization 1.0.0 vs 1.0.0.0", "1.0.0", "1.0.0.0", 0},
+	// Real-world messy versions
+	{"messy rc dash", "1.0.0-rc1", "1.0.0rc1", 0}, // Should normalize
+	{"messy post dash", "1.0.0-post1", "1.0.0.post1", 0},
+	{"messy dev dash", "1.0.0-dev1", "1.0.0.dev1", 0},
+	// Pre-release vs dev/post
+	{"pre vs dev", "1.0.0a1", "1.0.0.dev1", 1},
+	{"pre vs post", "1.0.0a1", "1.0.0.post1", -1},
+	// Epoch with pre/post/dev
+	{"epoch with pre", "1!1.0.0a1", "1.0.0a1", 1},
+	{"epoch with post", "1!1.0.0.post1", "1.0.0.post1", 1},
+	// Leading zeros (should normalize)
+	{"leading zeros", "1.02.3", "1.2.3", 0},
+	// Complex real-world
+	{"complex dev/post", "1.0.0.post1.dev2", "1.0.0.post1.dev3", -1},
+	// Explicit zero segments are not the same as absent ones
+	{"dev0 < final", "1.0.dev0", "1.0", -1},
+	{"post0 > final", "1.0.post0", "1.0", 1},
+	{"dev0 < dev1", "1.0.dev0", "1.0.dev1", -1},
+	{"post0 < post1", "1.0.post0", "1.0.post1", -1},
+	{"dev0 < a0", "1.0.dev0", "1.0a0", -1},
+	{"implicit post0", "1.0-0", "1.0.post0", 0},
+	// Numerals beyond the int range
+	{"huge release lt", "1.20240101123045123456788", "1.20240101123045123456789", -1},
+	{"huge release vs small", "1.9223372036854775807", "1.9223372036854775808", -1},
+	{"huge release eq leading zeros", "1.0020240101123045123456789", "1.20240101123045123456789", 0},
+	{"huge epoch", "99999999999999999999!1.0", "9223372036854775807!2.0", 1},
+	{"huge pre", "1.0a99999999999999999999", "1.0b0", -1},
+	{"huge post", "1.0.post99999999999999999999", "1.0.post99999999999999999998", 1},
+	{"huge dev", "1.0.dev99999999999999999999", "1.0a0", -1},
+	{"huge local numeric", "1.0+99999999999999999999", "1.0+abc", 1},
+	{"local leading zeros", "1.0+007", "1.0+7", 0},
+}
 
-	for _, tc := range tests {
+func TestCompare(t *testing.T) {
+	for _, tc := range compareCases {
 		t.Run(tc.name, func(t *testing.T) {
 			v1, err1 := Parse(tc.v1)
 			v2, err2 := Parse(tc.v2)
@@ -65,3 +83,64 @@ func TestCompare(t *testing.T) {
 		})
 	}
 }
+
+// orderedVersions is strictly increasing per the PEP 440 comparison key.
+var orderedVersions = []string{
+	"1.0.dev0",
+	"1.0.dev456",
+	"1.0a1.dev1",
+	"1.0a1",
+	"1.0a1.post1.dev1",
+	"1.0a1.post1",
+	"1.0a2.dev1",
+	"1.0a2.dev456",
+	"1.0a12.dev456",
+	"1.0a12",
+	"1.0b1.dev456",
+	"1.0b2",
+	"1.0b2.post345.dev456",
+	"1.0b2.post345",
+	"1.0b2-346",
+	"1.0c1.dev456",
+	"1.0c1",
+	"1.0rc2",
+	"1.0c3",
+	"1.0",
+	"1.0+abc",
+	"1.0.post0.dev0",
+	"1.0.post0",
+	"1.0.post1.dev1",
+	"1.0.post1",
+	"1.0.post456.dev34",
+	"1.0.post456",
+	"1.0.1.dev1",
+	"1.1.dev1",
+	"1.1a1",
+	"1.1",
+	"1!0.1",
+}
+
+func TestCompareCombinedSegments(t *testing.T) {
+	// Every pair is checked.
+	vs := make([]Version, len(orderedVersions))
+	for i, s := range orderedVersions {
+		v, err := Parse(s)
+		if err != nil {
+			t.Fatalf("parse error for %q: %v", s, err)
+		}
+		vs[i] = v
+	}
+	for i := range vs {
+		for j := range vs {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := Compare(vs[i], vs[j]); got != want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", orderedVersions[i], orderedVersions[j], got, want)
+			}
+		}
+	}
+}
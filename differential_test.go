@@ -0,0 +1,277 @@
+package pyver
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var (
+	differentialCount  = flag.Int("differential.count", 2000, "number of generated inputs TestDifferential checks against packaging")
+	differentialSeed   = flag.Int64("differential.seed", 1, "seed for the inputs TestDifferential generates")
+	differentialRecord = flag.Bool("differential.record", false, "append the disagreements TestDifferential finds to "+differentialCorpus)
+)
+
+// differentialCorpus holds the inputs on which the native parser once
+// disagreed with packaging, together with packaging's answer. It is replayed
+// by TestDifferentialCorpus, which needs no Python.
+const differentialCorpus = "testdata/differential.jsonl"
+
+// A diffCase is packaging's answer for one input: its normalized form, or,
+// when Other is set, how it orders against Other.
+type diffCase struct {
+	Input      string  `json:"input"`
+	Other      string  `json:"other,omitempty"`
+	Normalized *string `json:"normalized,omitempty"` // nil if packaging rejects Input
+	Compare    int     `json:"compare,omitempty"`
+}
+
+func (c diffCase) String() string {
+	b, _ := json.Marshal(c)
+	return string(b)
+}
+
+// check reports how the native implementation disagrees with c.
+func (c diffCase) check() error {
+	if c.Other != "" {
+		a, err1 := parseGoNative(c.Input)
+		b, err2 := parseGoNative(c.Other)
+		if err := errors.Join(err1, err2); err != nil {
+			return err
+		}
+		if got := compareGoNative(a, b); got != c.Compare {
+			return fmt.Errorf("Compare(%q, %q) = %d, packaging gives %d", c.Input, c.Other, got, c.Compare)
+		}
+		return nil
+	}
+	v, err := parseGoNative(c.Input)
+	switch {
+	case c.Normalized == nil && err == nil:
+		return fmt.Errorf("Parse(%q) = %q, packaging rejects it", c.Input, v.Normalized)
+	case c.Normalized != nil && err != nil:
+		return fmt.Errorf("Parse(%q): %v, packaging gives %q", c.Input, err, *c.Normalized)
+	case c.Normalized != nil && v.Normalized != *c.Normalized:
+		return fmt.Errorf("Parse(%q) = %q, packaging gives %q", c.Input, v.Normalized, *c.Normalized)
+	}
+	return nil
+}
+
+// packagingParse asks packaging, through w, how it parses s.
+func packagingParse(w *Worker, s string) (diffCase, error) {
+	c := diffCase{Input: s}
+	v, err := w.Parse(s)
+	switch {
+	case err == nil:
+		c.Normalized = &v.Normalized
+	case !errors.Is(err, ErrInvalidVersion):
+		return c, err
+	}
+	return c, nil
+}
+
+// packagingCompare asks packaging, through w, how a orders against b.
+func packagingCompare(w *Worker, a, b Version) (diffCase, error) {
+	n, err := w.Compare(a, b)
+	return diffCase{Input: a.Original, Other: b.Original, Compare: n}, err
+}
+
+// knownDifference reports why packaging is expected to disagree with the
+// native parser on s, or returns "" if the two should agree.
+func knownDifference(s string) string {
+	// Python matches these against the ASCII letters of the version pattern
+	// when ignoring case, but keeps them in the parsed segments, accepting
+	// versions such as "1.0prevıew1" that PEP 440 does not allow.
+	if strings.ContainsAny(s, "\u0130\u0131\u017f\u212a") {
+		return "non-ASCII letter matched by case folding"
+	}
+	// Python refuses to convert longer digit strings to integers.
+	run := 0
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			run = 0
+			continue
+		}
+		if run++; run > 4300 {
+			return "numeral beyond Python's integer string limit"
+		}
+	}
+	return ""
+}
+
+// differentialWorker returns a Worker for asking packaging, or nil when the
+// Python backend is unavailable.
+var differentialWorker = sync.OnceValue(func() *Worker {
+	w := &Worker{}
+	if _, err := w.Parse("1.0"); err != nil {
+		w.Close()
+		return nil
+	}
+	return w
+})
+
+func requireDifferentialWorker(t *testing.T) *Worker {
+	t.Helper()
+	w := differentialWorker()
+	if w == nil {
+		t.Skip("python backend unavailable")
+	}
+	return w
+}
+
+// pep440ish returns a version-like string: a valid version with spellings,
+// separators, whitespace and stray characters inserted at random places, so
+// that both valid and invalid inputs near the grammar's edges come up.
+func pep440ish(r *rand.Rand) string {
+	tokens := []string{
+		"0", "1", "00", "007", "10", "99999999999999999999",
+		".", ".", "-", "_", "!", "+", "v", "V",
+		"a", "A", "alpha", "b", "Beta", "c", "rc", "RC", "pre", "Preview",
+		"post", "rev", "r", "dev", "DEV", "abc", "x", "é",
+		" ", "\t", "\n", "\x1c", "\x1f", "\u0085", "\u3000", "\u200b", "\ufeff",
+	}
+	var s []rune
+	if r.Intn(8) != 0 {
+		s = []rune(randomVersion(r))
+	}
+	for range r.Intn(4) {
+		i := r.Intn(len(s) + 1)
+		s = append(s[:i], append([]rune(tokens[r.Intn(len(tokens))]), s[i:]...)...)
+	}
+	return string(s)
+}
+
+func loadCorpus(tb testing.TB) []diffCase {
+	tb.Helper()
+	f, err := os.Open(differentialCorpus)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		tb.Fatal(err)
+	}
+	defer f.Close()
+	var cases []diffCase
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		if len(strings.TrimSpace(sc.Text())) == 0 {
+			continue
+		}
+		var c diffCase
+		if err := json.Unmarshal(sc.Bytes(), &c); err != nil {
+			tb.Fatalf("%s: %v", differentialCorpus, err)
+		}
+		cases = append(cases, c)
+	}
+	if err := sc.Err(); err != nil {
+		tb.Fatal(err)
+	}
+	return cases
+}
+
+// recordCorpus appends the cases not yet in the corpus.
+func recordCorpus(t *testing.T, cases []diffCase) {
+	t.Helper()
+	seen := make(map[[2]string]bool)
+	for _, c := range loadCorpus(t) {
+		seen[[2]string{c.Input, c.Other}] = true
+	}
+	if err := os.MkdirAll("testdata", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(differentialCorpus, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+	for _, c := range cases {
+		if k := [2]string{c.Input, c.Other}; !seen[k] {
+			seen[k] = true
+			if err := enc.Encode(c); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// TestDifferential checks generated inputs against packaging: whether they
+// are valid, how they normalize and how they order. With
+// -differential.record the disagreements are added to the corpus.
+func TestDifferential(t *testing.T) {
+	w := requireDifferentialWorker(t)
+	r := rand.New(rand.NewSource(*differentialSeed))
+	var found []diffCase
+	disagree := func(c diffCase, err error) {
+		t.Error(err)
+		found = append(found, c)
+	}
+
+	var valid []Version
+	for range *differentialCount {
+		s := pep440ish(r)
+		if knownDifference(s) != "" {
+			continue
+		}
+		c, err := packagingParse(w, s)
+		if err != nil {
+			t.Fatalf("packaging: %q: %v", s, err)
+		}
+		if err := c.check(); err != nil {
+			disagree(c, err)
+		} else if c.Normalized != nil {
+			valid = append(valid, MustParse(s))
+		}
+	}
+	for i, a := range valid {
+		// Neighbours in generation order, and a version against its own
+		// normalized form.
+		for _, b := range []Version{valid[(i+1)%len(valid)], MustParse(a.Normalized)} {
+			c, err := packagingCompare(w, a, b)
+			if err != nil {
+				t.Fatalf("packaging: %q, %q: %v", a.Original, b.Original, err)
+			}
+			if err := c.check(); err != nil {
+				disagree(c, err)
+			}
+		}
+	}
+
+	if len(found) > 0 && *differentialRecord {
+		recordCorpus(t, found)
+		t.Logf("recorded %d disagreements in %s", len(found), differentialCorpus)
+	}
+}
+
+// TestDifferentialCorpus replays the recorded disagreements. When Python is
+// available it also confirms that packaging still gives the recorded answers.
+func TestDifferentialCorpus(t *testing.T) {
+	w := differentialWorker()
+	for _, c := range loadCorpus(t) {
+		if err := c.check(); err != nil {
+			t.Error(err)
+		}
+		if w == nil {
+			continue
+		}
+		var got diffCase
+		var err error
+		if c.Other != "" {
+			got, err = packagingCompare(w, MustParse(c.Input), MustParse(c.Other))
+		} else {
+			got, err = packagingParse(w, c.Input)
+		}
+		if err != nil {
+			t.Errorf("packaging: %q: %v", c.Input, err)
+		} else if got.String() != c.String() {
+			t.Errorf("packaging now gives %s, corpus has %s", got, c)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package pyver
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDistroMapping is returned by Version.Debian and Version.RPM for a
+// version that has no order-preserving distro equivalent.
+var ErrDistroMapping = errors.New("no distro version mapping")
+
+// Debian returns v as a Debian upstream version, with the epoch in front
+// when it is not zero, so that dpkg orders mapped versions as Compare
+// does (see CompareDebian):
+//
+//	1.0rc1 -> 1.0~rc1
+//	1.0a1.dev2 -> 1.0~a1~dev2
+//	1.0.dev1 -> 1.0~~dev1
+//	1.0.post1 -> 1.0+post1
+//	1!2.0 -> 1:2.0
+//
+// The release has trailing zeros removed but keeps at least two
+// components, as dpkg sorts "1.0.0" after "1.0": "1.0.0rc1" -> "1.0~rc1",
+// "2" -> "2.0". A local version has no equivalent, as a local label
+// would sort after the post-releases it precedes; use WithoutLocal to map
+// the public version.
+func (v Version) Debian() (string, error) {
+	return v.distroVersion("+")
+}
+
+// RPM returns v as an RPM version, with the epoch in front when it is not
+// zero, so that rpm orders mapped versions as Compare does (see
+// CompareRPM). It follows Debian, except that a post-release is written
+// with a caret, which sorts after the end of a version: "1.0.post1" ->
+// "1.0^post1".
+func (v Version) RPM() (string, error) {
+	return v.distroVersion("^")
+}
+
+// distroVersion writes v with "~" before pre- and dev-releases and post
+// before post-releases.
+func (v Version) distroVersion(post string) (string, error) {
+	if len(v.Local) > 0 {
+		return "", fmt.Errorf("%w %q: a local version has no equivalent", ErrDistroMapping, v.String())
+	}
+	var b strings.Builder
+	if e := v.epochNumeral(); !e.isZero() {
+		b.WriteString(e.String() + ":")
+	}
+	for i := range max(releaseLen(v), 2) {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		if i < len(v.Release) {
+			b.WriteString(v.releaseNumeral(i).String())
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	if v.PreKind != "" {
+		b.WriteString("~" + v.PreKind + v.preNumeral().String())
+	}
+	if v.HasPost {
+		b.WriteString(post + "post" + v.postNumeral().String())
+	}
+	if v.HasDev {
+		// A dev-release of a release sorts before its pre-releases:
+		// "~~dev" before "~a".
+		if v.PreKind == "" && !v.HasPost {
+			b.WriteByte('~')
+		}
+		b.WriteString("~dev" + v.devNumeral().String())
+	}
+	return b.String(), nil
+}
+
+// splitEVR splits "[epoch:]version[-revision]" into its parts. The epoch
+// is "0" and the revision empty when absent.
+func splitEVR(s string) (epoch, version, revision string) {
+	epoch, version = "0", s
+	if e, rest, ok := strings.Cut(s, ":"); ok && isDigits(e) {
+		epoch, version = e, rest
+	}
+	if i := strings.LastIndexByte(version, '-'); i >= 0 {
+		version, revision = version[:i], version[i+1:]
+	}
+	return epoch, version, revision
+}
+
+// compareDigits compares strings of decimal digits by value.
+func compareDigits(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	return cmp.Or(cmp.Compare(len(a), len(b)), strings.Compare(a, b))
+}
+
+// CompareDebian compares Debian versions "[epoch:]upstream[-revision]" as
+// dpkg --compare-versions does, returning -1, 0 or 1. Within the upstream
+// version and revision, "~" sorts before anything, even the end of the
+// string, letters before other characters, and digit runs by value:
+//
+//	1.0~rc1 < 1.0 < 1.0+post1 < 1.0.1
+func CompareDebian(a, b string) int {
+	ea, va, ra := splitEVR(a)
+	eb, vb, rb := splitEVR(b)
+	return cmp.Or(compareDigits(ea, eb), verrevcmp(va, vb), verrevcmp(ra, rb))
+}
+
+// verrevcmp is dpkg's comparison of upstream versions and revisions.
+func verrevcmp(a, b string) int {
+	// order ranks a non-digit character; 0 stands for the end or a digit.
+	order := func(s string, i int) int {
+		switch {
+		case i >= len(s) || isDigit(s[i]):
+			return 0
+		case isLetter(s[i]):
+			return int(s[i])
+		case s[i] == '~':
+			return -1
+		}
+		return int(s[i]) + 256
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && !isDigit(a[i]) || j < len(b) && !isDigit(b[j]) {
+			if c := cmp.Compare(order(a, i), order(b, j)); c != 0 {
+				return c
+			}
+			i, j = i+1, j+1
+		}
+		si, sj := i, j
+		for i < len(a) && isDigit(a[i]) {
+			i++
+		}
+		for j < len(b) && isDigit(b[j]) {
+			j++
+		}
+		if c := compareDigits(a[si:i], b[sj:j]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// CompareRPM compares RPM versions "[epoch:]version[-release]" as rpm
+// does, comparing the epochs and then the versions and releases with
+// rpmvercmp, returning -1, 0 or 1. Characters other than letters, digits,
+// "~" and "^" only separate segments; "~" sorts before anything, "^"
+// after the end of a version but before anything else, digit segments by
+// value and after letter segments:
+//
+//	1.0~rc1 < 1.0 < 1.0^post1 < 1.0.1
+func CompareRPM(a, b string) int {
+	ea, va, ra := splitEVR(a)
+	eb, vb, rb := splitEVR(b)
+	return cmp.Or(compareDigits(ea, eb), rpmvercmp(va, vb), rpmvercmp(ra, rb))
+}
+
+// rpmvercmp is rpm's comparison of versions and releases.
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	// skip drops the separators before the next segment.
+	skip := func(s string) string {
+		i := 0
+		for i < len(s) && !isDigit(s[i]) && !isLetter(s[i]) && s[i] != '~' && s[i] != '^' {
+			i++
+		}
+		return s[i:]
+	}
+	for len(a) > 0 || len(b) > 0 {
+		a, b = skip(a), skip(b)
+		switch {
+		case strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~"):
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		case strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^"):
+			switch {
+			case a == "":
+				return -1
+			case b == "":
+				return 1
+			case a[0] != '^':
+				return 1
+			case b[0] != '^':
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		if a == "" || b == "" {
+			break
+		}
+		class := isLetter
+		if isDigit(a[0]) {
+			class = isDigit
+		}
+		n, m := 0, 0
+		for n < len(a) && class(a[n]) {
+			n++
+		}
+		for m < len(b) && class(b[m]) {
+			m++
+		}
+		if m == 0 {
+			// Digit segments are newer than letter segments.
+			if isDigit(a[0]) {
+				return 1
+			}
+			return -1
+		}
+		var c int
+		if isDigit(a[0]) {
+			c = compareDigits(a[:n], b[:m])
+		} else {
+			c = strings.Compare(a[:n], b[:m])
+		}
+		if c != 0 {
+			return c
+		}
+		a, b = a[n:], b[m:]
+	}
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
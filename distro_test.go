@@ -0,0 +1,167 @@
+package pyver
+
+import (
+	"errors"
+	"math/rand"
+	"os/exec"
+	"testing"
+)
+
+func TestVersionDistro(t *testing.T) {
+	tests := []struct {
+		input, debian, rpm string
+	}{
+		{"1.0", "1.0", "1.0"},
+		{"1.0rc1", "1.0~rc1", "1.0~rc1"},
+		{"1.0.0rc1", "1.0~rc1", "1.0~rc1"},
+		{"2", "2.0", "2.0"},
+		{"1.2.3", "1.2.3", "1.2.3"},
+		{"1.0a1.dev2", "1.0~a1~dev2", "1.0~a1~dev2"},
+		{"1.0.dev1", "1.0~~dev1", "1.0~~dev1"},
+		{"1.0.post1", "1.0+post1", "1.0^post1"},
+		{"1.0.post1.dev2", "1.0+post1~dev2", "1.0^post1~dev2"},
+		{"1.0b2.post1", "1.0~b2+post1", "1.0~b2^post1"},
+		{"1!2.0", "1:2.0", "1:2.0"},
+	}
+	for _, tc := range tests {
+		v := MustParse(tc.input)
+		deb, err := v.Debian()
+		if err != nil || deb != tc.debian {
+			t.Errorf("%q.Debian() = %q, %v, want %q", tc.input, deb, err, tc.debian)
+		}
+		rpm, err := v.RPM()
+		if err != nil || rpm != tc.rpm {
+			t.Errorf("%q.RPM() = %q, %v, want %q", tc.input, rpm, err, tc.rpm)
+		}
+	}
+	if s, err := MustParse("1.0+abc").Debian(); !errors.Is(err, ErrDistroMapping) {
+		t.Errorf("1.0+abc.Debian() = %q, %v, want ErrDistroMapping", s, err)
+	}
+}
+
+func TestCompareDebian(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.00", 0},
+		{"1.0", "1.0-0", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0~", "1.0", -1},
+		{"1.0", "1.0a", -1},
+		{"1.0a", "1.0+", -1},
+		{"1.0+", "1.0.", -1},
+		{"1.0+post1", "1.0.1", -1},
+		{"1:0.1", "2.0", 1},
+		{"1.0-1", "1.0-2", -1},
+		{"1.0-10", "1.0-9", 1},
+		{"2.0", "10.0", -1},
+	}
+	for _, tc := range tests {
+		if got := CompareDebian(tc.a, tc.b); got != tc.want {
+			t.Errorf("CompareDebian(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+		if got := CompareDebian(tc.b, tc.a); got != -tc.want {
+			t.Errorf("CompareDebian(%q, %q) = %d, want %d", tc.b, tc.a, got, -tc.want)
+		}
+	}
+}
+
+func TestCompareRPM(t *testing.T) {
+	// Cases from rpm's rpmvercmp tests.
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0.1", "2.0.1a", -1},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"10xyz", "10.1xyz", -1},
+		{"xyz10", "xyz10.1", -1},
+		{"xyz.4", "8", -1},
+		{"1.0aa", "1.0a", 1},
+		{"2.0", "2_0", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~rc1~git123", "1.0~rc1", -1},
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.01", -1},
+		{"1.0^git1", "1.0^git2", -1},
+		{"1.0^git1", "1.0.1", -1},
+		{"1.0^git1~pre", "1.0^git1", -1},
+		{"1.0~rc1^git1", "1.0~rc1", 1},
+		{"1:1.0", "2.0", 1},
+		{"1.0-1", "1.0-2", -1},
+	}
+	for _, tc := range tests {
+		if got := CompareRPM(tc.a, tc.b); got != tc.want {
+			t.Errorf("CompareRPM(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+		if got := CompareRPM(tc.b, tc.a); got != -tc.want {
+			t.Errorf("CompareRPM(%q, %q) = %d, want %d", tc.b, tc.a, got, -tc.want)
+		}
+	}
+}
+
+// TestDistroOrder checks that dpkg and rpm order the mapped versions as
+// Compare orders the versions.
+func TestDistroOrder(t *testing.T) {
+	var vs []Version
+	for _, v := range append(regionVersions(), parseAll([]string{"1!1.0", "1!0.5.post1", "0.0.0", "1.0.0.0.1"})...) {
+		if len(v.Local) == 0 {
+			vs = append(vs, v)
+		}
+	}
+	deb, rpm := make([]string, len(vs)), make([]string, len(vs))
+	for i, v := range vs {
+		deb[i], _ = v.Debian()
+		rpm[i], _ = v.RPM()
+	}
+	for i := range vs {
+		for j := range vs {
+			want := vs[i].Compare(vs[j])
+			if got := CompareDebian(deb[i], deb[j]); got != want {
+				t.Errorf("CompareDebian(%q, %q) = %d, Compare(%q, %q) = %d", deb[i], deb[j], got, vs[i], vs[j], want)
+			}
+			if got := CompareRPM(rpm[i], rpm[j]); got != want {
+				t.Errorf("CompareRPM(%q, %q) = %d, Compare(%q, %q) = %d", rpm[i], rpm[j], got, vs[i], vs[j], want)
+			}
+		}
+	}
+}
+
+// TestCompareDebianDpkg checks CompareDebian against dpkg on random
+// versions, if dpkg is installed.
+func TestCompareDebianDpkg(t *testing.T) {
+	if _, err := exec.LookPath("dpkg"); err != nil {
+		t.Skip("dpkg not installed")
+	}
+	const chars = "0019a~+."
+	r := rand.New(rand.NewSource(1))
+	random := func() string {
+		b := []byte{"0123456789"[r.Intn(10)]}
+		for range r.Intn(6) {
+			b = append(b, chars[r.Intn(len(chars))])
+		}
+		if r.Intn(3) == 0 {
+			b = append(b, '-', chars[r.Intn(4)])
+		}
+		return string(b)
+	}
+	for range 200 {
+		a, b := random(), random()
+		want := 2
+		for op, c := range map[string]int{"lt": -1, "eq": 0, "gt": 1} {
+			if exec.Command("dpkg", "--compare-versions", a, op, b).Run() == nil {
+				want = c
+			}
+		}
+		if got := CompareDebian(a, b); got != want {
+			t.Errorf("CompareDebian(%q, %q) = %d, dpkg gives %d", a, b, got, want)
+		}
+	}
+}
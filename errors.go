@@ -0,0 +1,136 @@
+package pyver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sentinel errors for use with errors.Is.
+var (
+	// ErrInvalidVersion is matched by every *ParseError.
+	ErrInvalidVersion = errors.New("invalid version")
+
+	// ErrBackend reports a failure of the Python backend itself (missing
+	// interpreter or script, unexpected output) rather than of the input.
+	ErrBackend = errors.New("pyver backend error")
+)
+
+// Segment names the part of a version string a ParseError refers to.
+type Segment string
+
+const (
+	SegmentEpoch   Segment = "epoch"
+	SegmentRelease Segment = "release"
+	SegmentPre     Segment = "pre"
+	SegmentPost    Segment = "post"
+	SegmentDev     Segment = "dev"
+	SegmentLocal   Segment = "local"
+)
+
+// Reason is a machine-readable code describing why parsing failed.
+type Reason string
+
+const (
+	// ReasonEmpty means the input was empty or only whitespace.
+	ReasonEmpty Reason = "empty"
+	// ReasonUnexpectedEnd means the input ended where a number or label was
+	// required, as in "1.0." or "1.0+".
+	ReasonUnexpectedEnd Reason = "unexpected-end"
+	// ReasonMissingNumber means a numeral was required but another
+	// character was found, as in "1..0" or ".1".
+	ReasonMissingNumber Reason = "missing-number"
+	// ReasonUnexpectedCharacter means a character is not allowed at its
+	// position, as in "1.0@abc" or "1.0+abc+def".
+	ReasonUnexpectedCharacter Reason = "unexpected-character"
+	// ReasonDuplicateSegment means a segment occurs twice, as in "1.0a1a2".
+	ReasonDuplicateSegment Reason = "duplicate-segment"
+	// ReasonMisorderedSegment means a segment follows one it must precede,
+	// as in "1.0.dev1.post1".
+	ReasonMisorderedSegment Reason = "misordered-segment"
+	// ReasonInvalid is used when no more specific cause is known.
+	ReasonInvalid Reason = "invalid"
+)
+
+var reasonText = map[Reason]string{
+	ReasonEmpty:               "empty version string",
+	ReasonUnexpectedEnd:       "unexpected end of input",
+	ReasonMissingNumber:       "expected a number",
+	ReasonUnexpectedCharacter: "unexpected character",
+	ReasonDuplicateSegment:    "segment repeated",
+	ReasonMisorderedSegment:   "segment out of order",
+	ReasonInvalid:             "not a valid PEP 440 version",
+}
+
+// ParseError describes why a string is not a valid PEP 440 version. It is
+// returned by Parse in both the Go-native and the backend mode.
+type ParseError struct {
+	Input   string  // the string passed to Parse
+	Offset  int     // byte offset in Input where parsing failed
+	Segment Segment // segment being parsed at Offset
+	Reason  Reason  // machine-readable cause
+	Err     error   // sentinel matched by errors.Is, e.g. ErrInvalidVersion
+
+	// Normalizations lists the rules a non-canonical input relies on; it
+	// is only set by ParseStrict.
+	Normalizations []Normalization
+}
+
+func (e *ParseError) Error() string {
+	msg := reasonText[e.Reason]
+	if msg == "" {
+		msg = string(e.Reason)
+	}
+	switch e.Reason {
+	case ReasonEmpty:
+		return fmt.Sprintf("%v %q: %s", e.Err, e.Input, msg)
+	case ReasonNotCanonical:
+		rules := make([]string, len(e.Normalizations))
+		for i, n := range e.Normalizations {
+			rules[i] = n.String()
+		}
+		return fmt.Sprintf("%v %q: needs %s normalization (first at offset %d)", e.Err, e.Input, strings.Join(rules, ", "), e.Offset)
+	}
+	at := "end of input"
+	if e.Offset < len(e.Input) {
+		r, _ := utf8.DecodeRuneInString(e.Input[e.Offset:])
+		at = strconv.QuoteRune(r)
+	}
+	return fmt.Sprintf("%v %q: %s in %s segment at offset %d (%s)", e.Err, e.Input, msg, e.Segment, e.Offset, at)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// CanceledError is returned by the context-aware functions, such as
+// ParseContext, when the context is done before the backend answers. It
+// matches ErrBackend and the context's error, so a timeout satisfies
+// errors.Is(err, context.DeadlineExceeded).
+type CanceledError struct {
+	Op   string   // backend operation, e.g. "parse" or "compare"
+	Args []string // the version strings passed to it
+	Err  error    // the context's error
+}
+
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("%v: %s %q: %v", ErrBackend, e.Op, e.Args, e.Err)
+}
+
+func (e *CanceledError) Unwrap() []error { return []error{ErrBackend, e.Err} }
+
+// Timeout reports whether the call ran out of time, as opposed to being
+// canceled.
+func (e *CanceledError) Timeout() bool {
+	return errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+// invalidVersionError explains why s was rejected. The scanner locates the
+// failure; if it finds none, a generic error is returned.
+func invalidVersionError(s string) *ParseError {
+	if err := diagnose(s); err != nil {
+		return err
+	}
+	return &ParseError{Input: s, Segment: SegmentRelease, Reason: ReasonInvalid, Err: ErrInvalidVersion}
+}
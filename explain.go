@@ -0,0 +1,260 @@
+package pyver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Exclusion names the PEP 440 rule by which an exclusive comparison
+// rejects a version that the ordering alone would admit.
+type Exclusion int
+
+const (
+	// ExcludeNone means the clause applied no such rule.
+	ExcludeNone Exclusion = iota
+	// ExcludePre means <V rejected a pre-release of V's release:
+	// "<2" rejects "2.0rc1".
+	ExcludePre
+	// ExcludePost means >V rejected a post-release of V's release:
+	// ">1.0" rejects "1.0.post1".
+	ExcludePost
+	// ExcludeLocal means >V rejected a local version of V's release:
+	// ">1.0" rejects "1.0+abc".
+	ExcludeLocal
+)
+
+func (e Exclusion) String() string {
+	switch e {
+	case ExcludePre:
+		return "pre-releases"
+	case ExcludePost:
+		return "post-releases"
+	case ExcludeLocal:
+		return "local versions"
+	}
+	return "none"
+}
+
+// ClauseTrace records how one clause of a set decided on a version.
+type ClauseTrace struct {
+	Specifier Specifier
+	Satisfied bool
+	// LocalStripped reports whether the clause compared the version
+	// without its local label, as ~=, <=, >=, wildcards and == or !=
+	// without a local label do.
+	LocalStripped bool
+	// Prefix is the release prefix a wildcard or ~= clause matched the
+	// version against, such as "1.2" for "==1.2.*" and "1.4" for
+	// "~=1.4.2"; PrefixMatched reports whether it did. Prefix is empty for
+	// other clauses.
+	Prefix        string
+	PrefixMatched bool
+	// Exclusion is the rule that rejected a version the ordering admits.
+	Exclusion Exclusion
+	// Reason is a short human-readable account: "2.0rc1 > 1.5".
+	Reason string
+}
+
+// PreReleaseTrace records how a set's pre-release policy applied to a
+// version.
+type PreReleaseTrace struct {
+	Policy PreReleasePolicy
+	// Allowed reports whether the set admits pre-releases (see
+	// SpecifierSet.PreReleases).
+	Allowed bool
+	// MentionedBy is the first clause mentioning a pre-release when that is
+	// what admits them under the auto policy; empty otherwise.
+	MentionedBy string
+	// IsPrerelease reports whether the version is a pre-release.
+	IsPrerelease bool
+}
+
+// Rejected reports whether the policy alone rejects the version.
+func (p PreReleaseTrace) Rejected() bool {
+	return p.IsPrerelease && !p.Allowed
+}
+
+func (p PreReleaseTrace) String() string {
+	var why string
+	switch {
+	case p.Policy != PreReleasesAuto:
+		why = "by policy"
+	case p.MentionedBy != "":
+		why = "as " + p.MentionedBy + " mentions one"
+	default:
+		why = "as no clause mentions one"
+	}
+	verdict := "excluded"
+	if p.Allowed {
+		verdict = "allowed"
+	}
+	return verdict + " " + why
+}
+
+// Explanation is the trace returned by Explain.
+type Explanation struct {
+	Set     SpecifierSet
+	Version Version
+	// Satisfied equals Set.Contains(Version).
+	Satisfied  bool
+	PreRelease PreReleaseTrace
+	// Clauses holds a trace for every clause of Set, in the order of its
+	// String. The clauses are evaluated as if pre-releases were allowed,
+	// so a pre-release rejected by the policy still shows what they would
+	// decide.
+	Clauses []ClauseTrace
+}
+
+// Explain traces why v does or does not satisfy s: whether the pre-release
+// policy rejects it, and how every clause decides on it.
+//
+// The explanation of ">=1.5" for "2.0rc1" renders as
+//
+//	2.0rc1 does not satisfy >=1.5
+//	  pre-releases: excluded as no clause mentions one; 2.0rc1 is a pre-release
+//	  >=1.5: satisfied, 2.0rc1 > 1.5
+func Explain(s SpecifierSet, v Version) Explanation {
+	e := Explanation{
+		Set:       s,
+		Version:   v,
+		Satisfied: s.Contains(v),
+		PreRelease: PreReleaseTrace{
+			Policy:       s.policy,
+			Allowed:      s.PreReleases(),
+			IsPrerelease: v.IsPrerelease(),
+		},
+	}
+	specs := slices.SortedFunc(slices.Values(s.specs), func(a, b Specifier) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	if s.policy == PreReleasesAuto {
+		if i := slices.IndexFunc(specs, Specifier.PreReleases); i >= 0 {
+			e.PreRelease.MentionedBy = specs[i].String()
+		}
+	}
+	for _, sp := range specs {
+		e.Clauses = append(e.Clauses, sp.explain(v))
+	}
+	return e
+}
+
+// Rejected returns the traces of the clauses v does not satisfy.
+func (e Explanation) Rejected() []ClauseTrace {
+	var out []ClauseTrace
+	for _, c := range e.Clauses {
+		if !c.Satisfied {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// String renders the explanation as a verdict followed by a line for the
+// pre-release policy and one per clause.
+func (e Explanation) String() string {
+	var b strings.Builder
+	set := e.Set.String()
+	if set == "" {
+		set = "the empty set"
+	}
+	verdict := "satisfies"
+	if !e.Satisfied {
+		verdict = "does not satisfy"
+	}
+	fmt.Fprintf(&b, "%s %s %s\n", e.Version, verdict, set)
+	is := "is"
+	if !e.PreRelease.IsPrerelease {
+		is = "is not"
+	}
+	fmt.Fprintf(&b, "  pre-releases: %s; %s %s a pre-release\n", e.PreRelease, e.Version, is)
+	for _, c := range e.Clauses {
+		verdict := "satisfied"
+		if !c.Satisfied {
+			verdict = "rejected"
+		}
+		fmt.Fprintf(&b, "  %s: %s, %s\n", c.Specifier, verdict, c.Reason)
+	}
+	return b.String()
+}
+
+// explain traces contains(v, true).
+func (s Specifier) explain(v Version) ClauseTrace {
+	t := ClauseTrace{Specifier: s, Satisfied: s.contains(v, true)}
+	subject := v.String()
+	// strip records that the clause compares v without its local label.
+	strip := func() {
+		if len(v.Local) > 0 {
+			t.LocalStripped = true
+			subject = v.Public() + " (" + v.String() + " without its local label)"
+		}
+	}
+	switch s.op {
+	case OpCompatible:
+		strip()
+		t.Prefix = strings.TrimPrefix(compatiblePrefix(s.version), "0!")
+		t.PrefixMatched = prefixMatch(v, compatiblePrefix(s.version))
+		switch c := compareGoNative(public(v), s.v); {
+		case c < 0:
+			t.Reason = fmt.Sprintf("%s < %s", subject, s.v)
+		case !t.PrefixMatched:
+			t.Reason = fmt.Sprintf("%s does not match %s.*", subject, t.Prefix)
+		default:
+			t.Reason = fmt.Sprintf("%s %s %s and matches %s.*", subject, relation(c), s.v, t.Prefix)
+		}
+	case OpEqual, OpNotEqual:
+		if s.wildcard {
+			strip()
+			t.Prefix = strings.TrimSuffix(s.version, ".*")
+			t.PrefixMatched = prefixMatch(v, t.Prefix)
+			if t.PrefixMatched {
+				t.Reason = fmt.Sprintf("%s matches %s", subject, s.version)
+			} else {
+				t.Reason = fmt.Sprintf("%s does not match %s", subject, s.version)
+			}
+			break
+		}
+		w := v
+		if len(s.v.Local) == 0 {
+			strip()
+			w = public(v)
+		}
+		t.Reason = fmt.Sprintf("%s %s %s", subject, relation(compareGoNative(w, s.v)), s.v)
+	case OpLessEqual, OpGreaterEqual:
+		strip()
+		t.Reason = fmt.Sprintf("%s %s %s", subject, relation(compareGoNative(public(v), s.v)), s.v)
+	case OpLess, OpGreater:
+		c := compareGoNative(v, s.v)
+		t.Reason = fmt.Sprintf("%s %s %s", subject, relation(c), s.v)
+		if c == 0 || (c < 0) != (s.op == OpLess) || t.Satisfied {
+			break
+		}
+		switch {
+		case s.op == OpLess:
+			t.Exclusion = ExcludePre
+		case !s.v.IsPostrelease() && v.IsPostrelease():
+			t.Exclusion = ExcludePost
+		default:
+			t.Exclusion = ExcludeLocal
+		}
+		t.Reason += fmt.Sprintf(", but %s excludes the %s of %s", s, t.Exclusion, s.v.BaseVersion())
+	case OpArbitrary:
+		if t.Satisfied {
+			t.Reason = fmt.Sprintf("%q equals %q ignoring case", v.String(), s.version)
+		} else {
+			t.Reason = fmt.Sprintf("%q differs from %q", v.String(), s.version)
+		}
+	}
+	return t
+}
+
+// relation renders the sign of a comparison as an operator.
+func relation(c int) string {
+	switch {
+	case c < 0:
+		return "<"
+	case c > 0:
+		return ">"
+	}
+	return "=="
+}
@@ -0,0 +1,85 @@
+package pyver
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	e := Explain(MustParseSpecifierSet(">=1.5"), MustParse("2.0rc1"))
+	want := `2.0rc1 does not satisfy >=1.5
+  pre-releases: excluded as no clause mentions one; 2.0rc1 is a pre-release
+  >=1.5: satisfied, 2.0rc1 > 1.5
+`
+	if e.Satisfied || !e.PreRelease.Rejected() || len(e.Rejected()) != 0 || e.String() != want {
+		t.Errorf("Explain(>=1.5, 2.0rc1) =\n%s", e)
+	}
+
+	e = Explain(MustParseSpecifierSet(">=1.0a1,<2"), MustParse("1.5a1"))
+	if !e.Satisfied || e.PreRelease.MentionedBy != ">=1.0a1" {
+		t.Errorf("Explain(>=1.0a1,<2, 1.5a1) =\n%s", e)
+	}
+	e = Explain(MustParseSpecifierSet("").WithPreReleases(PreReleasesInclude), MustParse("1.5a1"))
+	if !strings.HasPrefix(e.String(), "1.5a1 satisfies the empty set\n  pre-releases: allowed by policy;") {
+		t.Errorf("Explain(\"\", 1.5a1) =\n%s", e)
+	}
+}
+
+func TestExplainClause(t *testing.T) {
+	tests := []struct {
+		spec, version string
+		exclusion     Exclusion
+		stripped      bool
+		prefix        string
+		reason        string
+	}{
+		{"<2", "2.0rc1", ExcludePre, false, "", "2.0rc1 < 2, but <2 excludes the pre-releases of 2"},
+		{"<2", "2.0", ExcludeNone, false, "", "2.0 == 2"},
+		{">1.0", "1.0.post1", ExcludePost, false, "", "1.0.post1 > 1.0, but >1.0 excludes the post-releases of 1.0"},
+		{">1.0", "1.0+abc", ExcludeLocal, false, "", "1.0+abc > 1.0, but >1.0 excludes the local versions of 1.0"},
+		{">1.0", "1.0.1+abc", ExcludeNone, false, "", "1.0.1+abc > 1.0"},
+		{"==1.0", "1.0+abc", ExcludeNone, true, "", "1.0 (1.0+abc without its local label) == 1.0"},
+		{"==1.0+abc", "1.0", ExcludeNone, false, "", "1.0 < 1.0+abc"},
+		{"==1.2.*", "1.2.post1", ExcludeNone, false, "1.2", "1.2.post1 matches 1.2.*"},
+		{"!=1.0.*", "1.1", ExcludeNone, false, "1.0", "1.1 does not match 1.0.*"},
+		{"~=1.4.2", "1.4.9", ExcludeNone, false, "1.4", "1.4.9 > 1.4.2 and matches 1.4.*"},
+		{"~=1.4.2", "1.5", ExcludeNone, false, "1.4", "1.5 does not match 1.4.*"},
+		{"~=1!1.4", "1.9", ExcludeNone, false, "1!1", "1.9 < 1!1.4"},
+		{"===1.0", "1.0.0", ExcludeNone, false, "", `"1.0.0" differs from "1.0"`},
+	}
+	for _, tc := range tests {
+		c := MustParseSpecifier(tc.spec).explain(MustParse(tc.version))
+		if c.Exclusion != tc.exclusion || c.LocalStripped != tc.stripped || c.Prefix != tc.prefix || c.Reason != tc.reason {
+			t.Errorf("%q.explain(%q) = %v %v %q %q, want %v %v %q %q", tc.spec, tc.version,
+				c.Exclusion, c.LocalStripped, c.Prefix, c.Reason, tc.exclusion, tc.stripped, tc.prefix, tc.reason)
+		}
+	}
+}
+
+// TestExplainContains checks that explanations agree with Contains.
+func TestExplainContains(t *testing.T) {
+	var clauses []string
+	for _, s := range specifierInputs() {
+		if _, err := ParseSpecifier(s); err == nil {
+			clauses = append(clauses, s)
+		}
+	}
+	vs := regionVersions()
+	r := rand.New(rand.NewSource(1))
+	for range 200 {
+		set := randomSpecifierSet(r, clauses)
+		for _, v := range vs {
+			e := Explain(set, v)
+			ok := !e.PreRelease.Rejected() && len(e.Rejected()) == 0
+			if e.Satisfied != set.Contains(v) || ok != e.Satisfied {
+				t.Fatalf("Explain(%q (%v), %q) =\n%s", set, set.policy, v, e)
+			}
+			for _, c := range e.Clauses {
+				if c.Exclusion != ExcludeNone && c.Satisfied {
+					t.Fatalf("Explain(%q, %q): %s is satisfied but has exclusion %v", set, v, c.Specifier, c.Exclusion)
+				}
+			}
+		}
+	}
+}
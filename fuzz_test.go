@@ -0,0 +1,99 @@
+package pyver
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+// The fuzz targets check properties of the native implementation and, when
+// the Python backend is available, compare it with packaging. Inputs that
+// fail are saved by the go tool under testdata/fuzz; run them with
+//
+//	make fuzz
+//
+// or go test -fuzz FuzzParse with GO_PYTHON pointing at an interpreter that
+// has packaging installed.
+
+// differs reports whether the differential check applies to s: JSON replaces
+// invalid UTF-8 on the way to Python, and knownDifference lists the rest.
+func differs(s string) bool {
+	return !utf8.ValidString(s) || knownDifference(s) != ""
+}
+
+func FuzzParse(f *testing.F) {
+	for _, s := range referenceInputs() {
+		f.Add(s)
+	}
+	for _, c := range loadCorpus(f) {
+		f.Add(c.Input)
+	}
+	w := differentialWorker()
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := parseGoNative(s)
+		if err != nil {
+			var pe *ParseError
+			if !errors.As(err, &pe) || pe.Offset < 0 || pe.Offset > len(s) {
+				t.Fatalf("Parse(%q) = %v, want a ParseError within the input", s, err)
+			}
+		} else {
+			n, err := parseGoNative(v.Normalized)
+			if err != nil || n.Normalized != v.Normalized || compareGoNative(n, v) != 0 {
+				t.Fatalf("Parse(%q) = %q, which reparses as %q, %v", s, v.Normalized, n.Normalized, err)
+			}
+			if k, err := ParseKey(v.Key()); err != nil || compareGoNative(k, v) != 0 {
+				t.Fatalf("ParseKey(Key(%q)) = %q, %v", s, k.Normalized, err)
+			}
+		}
+		if w == nil || differs(s) {
+			return
+		}
+		c, err := packagingParse(w, s)
+		if err != nil {
+			t.Fatalf("packaging: %q: %v", s, err)
+		}
+		if err := c.check(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzCompare(f *testing.F) {
+	for _, tc := range compareCases {
+		f.Add(tc.v1, tc.v2)
+	}
+	for i := 1; i < len(orderedVersions); i++ {
+		f.Add(orderedVersions[i-1], orderedVersions[i])
+	}
+	for _, c := range loadCorpus(f) {
+		if c.Other != "" {
+			f.Add(c.Input, c.Other)
+		}
+	}
+	w := differentialWorker()
+	f.Fuzz(func(t *testing.T, s1, s2 string) {
+		v1, err1 := parseGoNative(s1)
+		v2, err2 := parseGoNative(s2)
+		if err1 != nil || err2 != nil {
+			return
+		}
+		c := compareGoNative(v1, v2)
+		if r := compareGoNative(v2, v1); r != -c {
+			t.Fatalf("Compare(%q, %q) = %d but Compare(%q, %q) = %d", s1, s2, c, s2, s1, r)
+		}
+		if k := bytes.Compare(v1.Key(), v2.Key()); k != c {
+			t.Fatalf("Compare(%q, %q) = %d but the keys compare %d", s1, s2, c, k)
+		}
+		if w == nil || differs(s1) || differs(s2) {
+			return
+		}
+		d, err := packagingCompare(w, v1, v2)
+		if err != nil {
+			t.Fatalf("packaging: %q, %q: %v", s1, s2, err)
+		}
+		if err := d.check(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
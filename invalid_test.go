@@ -1,35 +1,102 @@
 package pyver
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
+
+// invalidVersionCases are rejected by Parse.
+var invalidVersionCases = []string{
+	"",                  // empty
+	"1..0.0",            // double dot
+	"1.0.",              // trailing dot
+	".1.0.0",            // leading dot
+	"1.0.0..1",          // double dot in middle
+	"1!1!1.0.0",         // multiple epochs
+	"1.0.0++abc",        // double plus
+	"1.0.0+abc+def",     // multiple local segments
+	"1.0.0@abc",         // invalid character
+	"1.0.0#meta",        // invalid character
+	"1.0.0..dev1",       // double dot before dev
+	"1.0.0.dev1.dev2",   // multiple dev segments
+	"1.0.0.post1.post2", // multiple post segments
+	"1.0.0a1a2",         // multiple pre segments
+	"1.0.0 dev1",        // space in version
+	"-1.0.0",            // negative release segment
+	"1.0.-1",            // negative release segment
+	"1.0.0+",            // local with no identifier
+	"1.0.0+abc..def",    // double dot in local
+}
 
 func TestInvalidVersions(t *testing.T) {
-	cases := []string{
-		"",                  // empty
-		"1..0.0",            // double dot
-		"1.0.",              // trailing dot
-		".1.0.0",            // leading dot
-		"1.0.0..1",          // double dot in middle
-		"1!1!1.0.0",         // multiple epochs
-		"1.0.0++abc",        // double plus
-		"1.0.0+abc+def",     // multiple local segments
-		"1.0.0@abc",         // invalid character
-		"1.0.0#meta",        // invalid character
-		"1.0.0..dev1",       // double dot before dev
-		"1.0.0.dev1.dev2",   // multiple dev segments
-		"1.0.0.post1.post2", // multiple post segments
-		"1.0.0a1a2",         // multiple pre segments
-		"1.0.0 dev1",        // space in version
-		"-1.0.0",            // negative release segment
-		"1.0.-1",            // negative release segment
-		"1.0.0+",            // local with no identifier
-		"1.0.0+abc..def",    // double dot in local
-	}
-	for _, s := range cases {
+	for _, s := range invalidVersionCases {
 		t.Run(s, func(t *testing.T) {
 			_, err := Parse(s)
 			if err == nil {
-				t.Errorf("expected error for version %q, got nil", s)
+				t.Fatalf("expected error for version %q, got nil", s)
+			}
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected *ParseError, got %T", err)
+			}
+			if !errors.Is(err, ErrInvalidVersion) {
+				t.Errorf("errors.Is(err, ErrInvalidVersion) = false for %v", err)
 			}
 		})
 	}
 }
+
+func TestParseErrorDetails(t *testing.T) {
+	cases := []struct {
+		input   string
+		offset  int
+		segment Segment
+		reason  Reason
+	}{
+		{"", 0, SegmentRelease, ReasonEmpty},
+		{"   ", 0, SegmentRelease, ReasonEmpty},
+		{"1..0.0", 2, SegmentRelease, ReasonMissingNumber},
+		{"1.0.", 4, SegmentRelease, ReasonUnexpectedEnd},
+		{".1.0.0", 0, SegmentRelease, ReasonMissingNumber},
+		{"  .1", 2, SegmentRelease, ReasonMissingNumber},
+		{"1!1!1.0.0", 3, SegmentEpoch, ReasonDuplicateSegment},
+		{"1.0!2", 3, SegmentEpoch, ReasonUnexpectedCharacter},
+		{"1!", 2, SegmentRelease, ReasonUnexpectedEnd},
+		{"1.0.0@abc", 5, SegmentRelease, ReasonUnexpectedCharacter},
+		{"1.0.0 dev1", 5, SegmentRelease, ReasonUnexpectedCharacter},
+		{"1.0.0a1a2", 7, SegmentPre, ReasonDuplicateSegment},
+		{"1.0.0.post1.post2", 11, SegmentPost, ReasonDuplicateSegment},
+		{"1.0.0.post1-2", 11, SegmentPost, ReasonDuplicateSegment},
+		{"1.0.0.dev1.dev2", 10, SegmentDev, ReasonDuplicateSegment},
+		{"1.0.dev1.post1", 8, SegmentPost, ReasonMisorderedSegment},
+		{"1.0.post1a1", 9, SegmentPre, ReasonMisorderedSegment},
+		{"1.0a1_1", 6, SegmentPre, ReasonUnexpectedCharacter},
+		{"1.0-", 4, SegmentRelease, ReasonUnexpectedEnd},
+		{"1.0.0+", 6, SegmentLocal, ReasonUnexpectedEnd},
+		{"1.0.0++abc", 6, SegmentLocal, ReasonUnexpectedCharacter},
+		{"1.0.0+abc+def", 9, SegmentLocal, ReasonUnexpectedCharacter},
+		{"1.0.0+abc..def", 10, SegmentLocal, ReasonUnexpectedCharacter},
+		{"1.0.0+abc.", 10, SegmentLocal, ReasonUnexpectedEnd},
+	}
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			if pe.Input != tc.input || pe.Offset != tc.offset || pe.Segment != tc.segment || pe.Reason != tc.reason {
+				t.Errorf("got {%q %d %s %s}, want {%q %d %s %s}",
+					pe.Input, pe.Offset, pe.Segment, pe.Reason, tc.input, tc.offset, tc.segment, tc.reason)
+			}
+		})
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	_, err := Parse("1..0")
+	want := `invalid version "1..0": expected a number in release segment at offset 2 ('.')`
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want %s", err, want)
+	}
+}
@@ -0,0 +1,258 @@
+package pyver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidKey is returned by ParseKey for bytes that Key did not produce.
+var ErrInvalidKey = errors.New("invalid version key")
+
+// Key layout. Each part of the comparison key is encoded so that byte order
+// matches Compare and no encoding is a prefix of another:
+//
+//	number   length byte + decimal digits without leading zeros (zero has
+//	         length 0); lengths of 255 and more are written as 0xff followed
+//	         by the length encoded as a number
+//	epoch    number
+//	release  keyItem + number per component, trailing zeros removed, then
+//	         keyEnd
+//	pre      0x00 for a dev-only release, 0x01/0x02/0x03 + number for
+//	         a/b/rc, 0x04 when absent
+//	post     0x00 when absent, 0x01 + number
+//	dev      0x01 + number, 0x02 when absent
+//	local    0x00 when absent; otherwise 0x01, then per segment 0x01 + the
+//	         lower-case text + 0x00 or 0x02 + number, then 0x00
+const (
+	keyEnd  = 0x01
+	keyItem = 0x02
+	keyLong = 0xff
+)
+
+var keyPreKinds = []string{"a", "b", "rc"}
+
+// Key returns a byte string whose lexicographic order matches Compare:
+// bytes.Compare(a.Key(), b.Key()) == Compare(a, b) for any valid a and b.
+// Equal versions, such as "1.0" and "1.0.0", have identical keys, so Key is
+// suitable for ordered key-value stores and database indexes. ParseKey
+// decodes it.
+func (v Version) Key() []byte {
+	b := make([]byte, 0, 16+2*len(v.Release))
+	b = appendKeyNumeral(b, v.epochNumeral())
+
+	// A release of only zeros keeps one component; [0] orders below every
+	// other stripped release just as the empty one would.
+	n := max(releaseLen(v), 1)
+	for i := range n {
+		b = append(b, keyItem)
+		if i < len(v.Release) {
+			b = appendKeyNumeral(b, v.releaseNumeral(i))
+		} else {
+			b = appendKeyNumeral(b, numeral{})
+		}
+	}
+	b = append(b, keyEnd)
+
+	rank, kind, num := preKey(v)
+	switch rank {
+	case negInf:
+		b = append(b, 0x00)
+	case posInf:
+		b = append(b, 0x04)
+	default:
+		b = appendKeyNumeral(append(b, byte(0x01+kind)), num)
+	}
+
+	if v.HasPost {
+		b = appendKeyNumeral(append(b, 0x01), v.postNumeral())
+	} else {
+		b = append(b, 0x00)
+	}
+
+	if v.HasDev {
+		b = appendKeyNumeral(append(b, 0x01), v.devNumeral())
+	} else {
+		b = append(b, 0x02)
+	}
+
+	if len(v.Local) == 0 {
+		return append(b, 0x00)
+	}
+	b = append(b, 0x01)
+	for _, s := range v.Local {
+		if isDigits(s) {
+			b = appendKeyNumeral(append(b, 0x02), parseNumeral(s))
+		} else {
+			b = append(append(append(b, 0x01), strings.ToLower(s)...), 0x00)
+		}
+	}
+	return append(b, 0x00)
+}
+
+func appendKeyNumeral(b []byte, n numeral) []byte {
+	digits := ""
+	if !n.isZero() {
+		digits = n.String()
+	}
+	if len(digits) < keyLong {
+		b = append(b, byte(len(digits)))
+	} else {
+		b = appendKeyNumeral(append(b, keyLong), numeral{n: len(digits)})
+	}
+	return append(b, digits...)
+}
+
+// ParseKey decodes a key produced by Version.Key. As equal versions share a
+// key, the result is the normalized form with trailing zeros removed from
+// the release, e.g. "1.0.0rc1" decodes to "1rc1"; it compares equal to the
+// encoded version.
+func ParseKey(key []byte) (Version, error) {
+	d := keyDecoder{key: key}
+	v := d.decode()
+	if d.err != nil {
+		return Version{}, d.err
+	}
+	v.Normalized = versionToString(v)
+	v.Original = v.Normalized
+	return v, nil
+}
+
+type keyDecoder struct {
+	key []byte
+	pos int
+	err error
+}
+
+func (d *keyDecoder) fail() {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w at byte %d", ErrInvalidKey, d.pos)
+	}
+}
+
+func (d *keyDecoder) next() byte {
+	if d.err != nil || d.pos >= len(d.key) {
+		d.fail()
+		return 0
+	}
+	c := d.key[d.pos]
+	d.pos++
+	return c
+}
+
+func (d *keyDecoder) numeral() numeral {
+	n := int(d.next())
+	if n == keyLong {
+		l := d.numeral()
+		if l.digits != "" || l.n < keyLong {
+			d.fail()
+		}
+		n = l.n
+	}
+	if d.err != nil || len(d.key)-d.pos < n {
+		d.fail()
+		return numeral{}
+	}
+	digits := string(d.key[d.pos : d.pos+n])
+	if n > 0 && (!isDigits(digits) || digits[0] == '0') {
+		d.fail()
+		return numeral{}
+	}
+	d.pos += n
+	return parseNumeral(digits)
+}
+
+func (d *keyDecoder) decode() Version {
+	var v Version
+	v.setEpoch(d.numeral())
+	for c := d.next(); c != keyEnd && d.err == nil; c = d.next() {
+		if c != keyItem {
+			d.fail()
+		}
+		v.appendRelease(d.numeral())
+	}
+	if n := len(v.Release); n == 0 || n > 1 && v.Release[n-1] == 0 {
+		d.fail()
+	}
+
+	pre := d.next()
+	if pre >= 0x01 && pre <= 0x03 {
+		v.setPre(keyPreKinds[pre-0x01], d.numeral())
+	} else if pre != 0x00 && pre != 0x04 {
+		d.fail()
+	}
+	switch d.next() {
+	case 0x00:
+	case 0x01:
+		v.setPost(d.numeral())
+	default:
+		d.fail()
+	}
+	switch d.next() {
+	case 0x01:
+		v.setDev(d.numeral())
+	case 0x02:
+	default:
+		d.fail()
+	}
+	// The pre-release sentinels must agree with the segments present.
+	if devOnly := v.HasDev && !v.HasPost; pre == 0x00 && !devOnly || pre == 0x04 && devOnly {
+		d.fail()
+	}
+
+	switch d.next() {
+	case 0x00:
+	case 0x01:
+		v.Local = d.local()
+	default:
+		d.fail()
+	}
+	if d.pos != len(d.key) {
+		d.fail()
+	}
+	return v
+}
+
+// local decodes the segments of a local label.
+func (d *keyDecoder) local() []string {
+	var local []string
+	for d.err == nil {
+		switch d.next() {
+		case 0x00:
+			if len(local) == 0 {
+				d.fail()
+			}
+			return local
+		case 0x01:
+			end := d.pos
+			for end < len(d.key) && d.key[end] != 0x00 {
+				end++
+			}
+			s := string(d.key[d.pos:end])
+			if end == len(d.key) || !isLocalLabel(s) {
+				d.fail()
+				return nil
+			}
+			d.pos = end + 1
+			local = append(local, s)
+		case 0x02:
+			local = append(local, d.numeral().String())
+		default:
+			d.fail()
+		}
+	}
+	return nil
+}
+
+// isLocalLabel reports whether s is a lower-case, non-numeric local segment.
+func isLocalLabel(s string) bool {
+	if s == "" || isDigits(s) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isAlnum(s[i]) || isLetter(s[i]) && s[i] < 'a' {
+			return false
+		}
+	}
+	return true
+}
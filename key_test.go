@@ -0,0 +1,164 @@
+package pyver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randomVersion builds a valid version string covering every segment,
+// zero and huge numerals, and trailing zeros in the release.
+func randomVersion(r *rand.Rand) string {
+	num := func() string {
+		switch r.Intn(8) {
+		case 0:
+			return "0"
+		case 1:
+			return "99999999999999999999"
+		case 2:
+			return "9223372036854775807"
+		}
+		return fmt.Sprint(r.Intn(4))
+	}
+	var b strings.Builder
+	if r.Intn(6) == 0 {
+		b.WriteString(num() + "!")
+	}
+	b.WriteString(num())
+	for range r.Intn(4) {
+		b.WriteString("." + num())
+	}
+	if r.Intn(2) == 0 {
+		b.WriteString([]string{"a", "b", "rc"}[r.Intn(3)] + num())
+	}
+	if r.Intn(3) == 0 {
+		b.WriteString(".post" + num())
+	}
+	if r.Intn(3) == 0 {
+		b.WriteString(".dev" + num())
+	}
+	if r.Intn(3) == 0 {
+		segs := []string{"abc", "ab", "1", "01", "2", "z9", "99999999999999999999"}
+		b.WriteString("+" + segs[r.Intn(len(segs))])
+		for range r.Intn(3) {
+			b.WriteString("." + segs[r.Intn(len(segs))])
+		}
+	}
+	return b.String()
+}
+
+func checkKeyOrder(t *testing.T, vs []Version) {
+	t.Helper()
+	keys := make([][]byte, len(vs))
+	for i, v := range vs {
+		keys[i] = v.Key()
+	}
+	for i := range vs {
+		for j := range vs {
+			if got, want := bytes.Compare(keys[i], keys[j]), Compare(vs[i], vs[j]); got != want {
+				t.Fatalf("bytes.Compare(Key(%q), Key(%q)) = %d, Compare = %d", vs[i].Original, vs[j].Original, got, want)
+			}
+		}
+	}
+}
+
+func TestKeyOrderTables(t *testing.T) {
+	var vs []Version
+	for _, s := range referenceInputs() {
+		if v, err := Parse(s); err == nil {
+			vs = append(vs, v)
+		}
+	}
+	checkKeyOrder(t, vs)
+}
+
+func TestKeyOrderRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	vs := make([]Version, 600)
+	for i := range vs {
+		vs[i] = MustParse(randomVersion(r))
+	}
+	checkKeyOrder(t, vs)
+}
+
+func TestKeyLongNumerals(t *testing.T) {
+	long := "1" + strings.Repeat("0", 300)
+	vs := []Version{
+		MustParse("1." + strings.Repeat("9", 254)),
+		MustParse("1." + strings.Repeat("1", 255)),
+		MustParse("1." + long),
+		MustParse("1." + long + "1"),
+		MustParse("1." + strings.Repeat("9", 301)),
+	}
+	checkKeyOrder(t, vs)
+	for _, v := range vs {
+		got, err := ParseKey(v.Key())
+		if err != nil || got.Normalized != v.Normalized {
+			t.Errorf("ParseKey(Key(%.12s...)) = %.12q..., %v", v.Normalized, got.Normalized, err)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"1.0", "1"},
+		{"0.0", "0"},
+		{"1!1.2.0rc1.post2.dev3+Abc.007", "1!1.2rc1.post2.dev3+abc.7"},
+		{"1.0.dev0", "1.dev0"},
+		{"1.0.post0.dev0", "1.post0.dev0"},
+		{"2.10a0+1.2", "2.10a0+1.2"},
+	}
+	for _, tc := range tests {
+		v := MustParse(tc.input)
+		got, err := ParseKey(v.Key())
+		if err != nil {
+			t.Fatalf("ParseKey(Key(%q)): %v", tc.input, err)
+		}
+		if got.String() != tc.want {
+			t.Errorf("ParseKey(Key(%q)) = %q, want %q", tc.input, got, tc.want)
+		}
+		if Compare(got, v) != 0 || !bytes.Equal(got.Key(), v.Key()) {
+			t.Errorf("ParseKey(Key(%q)) = %q does not equal the input", tc.input, got)
+		}
+	}
+
+	r := rand.New(rand.NewSource(2))
+	for range 2000 {
+		v := MustParse(randomVersion(r))
+		got, err := ParseKey(v.Key())
+		if err != nil {
+			t.Fatalf("ParseKey(Key(%q)): %v", v.Original, err)
+		}
+		if Compare(got, v) != 0 {
+			t.Fatalf("ParseKey(Key(%q)) = %q", v.Original, got)
+		}
+	}
+}
+
+func TestParseKeyInvalid(t *testing.T) {
+	valid := MustParse("1.0a1.post2.dev3+abc.4").Key()
+	cases := map[string][]byte{
+		"empty":         nil,
+		"trailing byte": append(bytes.Clone(valid), 0),
+		"no release":    {0x00, keyEnd, 0x04, 0x00, 0x02, 0x00},
+		"trailing zero": {0x00, keyItem, 0x01, '1', keyItem, 0x00, keyEnd, 0x04, 0x00, 0x02, 0x00},
+		"leading zero":  {0x00, keyItem, 0x02, '0', '1', keyEnd, 0x04, 0x00, 0x02, 0x00},
+		"bad pre":       {0x00, keyItem, 0x01, '1', keyEnd, 0x05, 0x00, 0x02, 0x00},
+		"sentinel":      {0x00, keyItem, 0x01, '1', keyEnd, 0x00, 0x00, 0x02, 0x00},
+		"empty local":   {0x00, keyItem, 0x01, '1', keyEnd, 0x04, 0x00, 0x02, 0x01, 0x00},
+		"upper local":   {0x00, keyItem, 0x01, '1', keyEnd, 0x04, 0x00, 0x02, 0x01, 0x01, 'A', 0x00, 0x00},
+	}
+	for i := range valid {
+		cases[fmt.Sprintf("truncated at %d", i)] = valid[:i]
+	}
+	for name, key := range cases {
+		if v, err := ParseKey(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("%s: ParseKey(%x) = %q, %v, want ErrInvalidKey", name, key, v, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package pyver
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// text returns the normalized form of v, rendering it from its fields when
+// the Version was built by hand, or "" for the zero Version.
+func (v Version) text() string {
+	if v.Normalized != "" {
+		return v.Normalized
+	}
+	if len(v.Release) == 0 {
+		return ""
+	}
+	return versionToString(v)
+}
+
+// MarshalText implements encoding.TextMarshaler using the normalized form.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.text()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The text must be a
+// valid version; it is parsed with Parse. Empty text is rejected like any
+// other invalid version, so a decoded Version always has a release: use
+// JSON null or SQL NULL for an absent version.
+func (v *Version) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. A Version is encoded as its
+// normalized string; the zero Version is encoded as null.
+func (v Version) MarshalJSON() ([]byte, error) {
+	s := v.text()
+	if s == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a version string,
+// the object form produced by StructuredVersion, or null, which leaves v
+// unchanged.
+func (v *Version) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '{':
+		var obj versionObject
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&obj); err != nil {
+			return err
+		}
+		s, err := obj.versionString()
+		if err != nil {
+			return err
+		}
+		return v.UnmarshalText([]byte(s))
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return v.UnmarshalText([]byte(s))
+}
+
+// StructuredVersion is a Version that marshals to JSON as an object holding
+// the individual segments, mirroring the attributes of packaging's Version:
+//
+//	{"version":"1!2.0rc1.post3+abc","epoch":1,"release":[2,0],
+//	 "pre":["rc",1],"post":3,"dev":null,"local":"abc"}
+//
+// It unmarshals from either the object or the string form.
+type StructuredVersion struct {
+	Version
+}
+
+// versionObject is the JSON object form of a Version.
+type versionObject struct {
+	Version string        `json:"version,omitempty"`
+	Epoch   json.Number   `json:"epoch"`
+	Release []json.Number `json:"release"`
+	Pre     []any         `json:"pre"`
+	Post    *json.Number  `json:"post"`
+	Dev     *json.Number  `json:"dev"`
+	Local   *string       `json:"local"`
+}
+
+// MarshalJSON implements json.Marshaler using the object form.
+func (sv StructuredVersion) MarshalJSON() ([]byte, error) {
+	v := sv.Version
+	if v.text() == "" {
+		return []byte("null"), nil
+	}
+	obj := versionObject{
+		Version: v.text(),
+		Epoch:   json.Number(v.epochNumeral().String()),
+		Release: make([]json.Number, len(v.Release)),
+	}
+	for i := range v.Release {
+		obj.Release[i] = json.Number(v.releaseNumeral(i).String())
+	}
+	if v.PreKind != "" {
+		obj.Pre = []any{v.PreKind, json.Number(v.preNumeral().String())}
+	}
+	if v.HasPost {
+		n := json.Number(v.postNumeral().String())
+		obj.Post = &n
+	}
+	if v.HasDev {
+		n := json.Number(v.devNumeral().String())
+		obj.Dev = &n
+	}
+	if len(v.Local) > 0 {
+		local := strings.Join(v.Local, ".")
+		obj.Local = &local
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either form.
+func (sv *StructuredVersion) UnmarshalJSON(data []byte) error {
+	return sv.Version.UnmarshalJSON(data)
+}
+
+// versionString rebuilds the version string described by the object. The
+// segments take precedence; "version" is used when no release is given.
+func (o versionObject) versionString() (string, error) {
+	if len(o.Release) == 0 {
+		if o.Version == "" {
+			return "", fmt.Errorf("pyver: version object has neither release nor version")
+		}
+		return o.Version, nil
+	}
+	var b strings.Builder
+	if o.Epoch != "" {
+		b.WriteString(o.Epoch.String())
+		b.WriteString("!")
+	}
+	for i, n := range o.Release {
+		if i > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(n.String())
+	}
+	if len(o.Pre) > 0 {
+		if len(o.Pre) != 2 {
+			return "", fmt.Errorf("pyver: version object pre must be [kind, number], got %v", o.Pre)
+		}
+		fmt.Fprintf(&b, "%v%v", o.Pre[0], o.Pre[1])
+	}
+	if o.Post != nil {
+		b.WriteString(".post")
+		b.WriteString(o.Post.String())
+	}
+	if o.Dev != nil {
+		b.WriteString(".dev")
+		b.WriteString(o.Dev.String())
+	}
+	if o.Local != nil {
+		b.WriteString("+")
+		b.WriteString(*o.Local)
+	}
+	return b.String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts a string or []byte holding a
+// valid version; a NULL column yields the zero Version.
+func (v *Version) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*v = Version{}
+		return nil
+	case string:
+		return v.UnmarshalText([]byte(src))
+	case []byte:
+		return v.UnmarshalText(src)
+	}
+	return fmt.Errorf("pyver: cannot scan %T into Version", src)
+}
+
+// Value implements driver.Valuer. A Version is stored as its normalized
+// string; the zero Version is stored as NULL.
+func (v Version) Value() (driver.Value, error) {
+	if s := v.text(); s != "" {
+		return s, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,164 @@
+package pyver
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type config struct {
+	Name    string   `json:"name"`
+	Version Version  `json:"version"`
+	Pinned  *Version `json:"pinned,omitempty"`
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	in := config{Name: "pkg", Version: MustParse("1.0-RC1")}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"pkg","version":"1.0rc1"}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if Compare(out.Version, in.Version) != 0 || out.Version.String() != "1.0rc1" {
+		t.Errorf("round trip: got %q, want %q", out.Version, in.Version)
+	}
+}
+
+func TestJSONZeroVersion(t *testing.T) {
+	data, err := json.Marshal(config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"","version":null}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Version.String() != "" {
+		t.Errorf("null decoded to %q, want zero Version", out.Version)
+	}
+}
+
+func TestJSONValidation(t *testing.T) {
+	var out config
+	err := json.Unmarshal([]byte(`{"version":"1.0+"}`), &out)
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Reason != ReasonUnexpectedEnd {
+		t.Errorf("invalid version: got %v, want *ParseError with %s", err, ReasonUnexpectedEnd)
+	}
+	if err := json.Unmarshal([]byte(`{"version":10}`), &out); err == nil {
+		t.Error("numeric version: expected an error")
+	}
+	if err := json.Unmarshal([]byte(`{"version":{"release":[1],"pre":["a"]}}`), &out); err == nil {
+		t.Error("malformed pre: expected an error")
+	}
+}
+
+func TestStructuredJSON(t *testing.T) {
+	cases := []struct {
+		input, want string
+	}{
+		{"1.0", `{"version":"1.0","epoch":0,"release":[1,0],"pre":null,"post":null,"dev":null,"local":null}`},
+		{"1!2.0rc1.post3+abc.5", `{"version":"1!2.0rc1.post3+abc.5","epoch":1,"release":[2,0],"pre":["rc",1],"post":3,"dev":null,"local":"abc.5"}`},
+		{"1.0.dev0", `{"version":"1.0.dev0","epoch":0,"release":[1,0],"pre":null,"post":null,"dev":0,"local":null}`},
+		{"1.99999999999999999999", `{"version":"1.99999999999999999999","epoch":0,"release":[1,99999999999999999999],"pre":null,"post":null,"dev":null,"local":null}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			data, err := json.Marshal(StructuredVersion{MustParse(tc.input)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tc.want {
+				t.Errorf("Marshal = %s, want %s", data, tc.want)
+			}
+			var sv StructuredVersion
+			if err := json.Unmarshal(data, &sv); err != nil {
+				t.Fatal(err)
+			}
+			if sv.String() != tc.input {
+				t.Errorf("round trip: got %q, want %q", sv.String(), tc.input)
+			}
+			var v Version
+			if err := json.Unmarshal(data, &v); err != nil {
+				t.Fatal(err)
+			}
+			if v.String() != tc.input {
+				t.Errorf("Version from object: got %q, want %q", v.String(), tc.input)
+			}
+		})
+	}
+}
+
+func TestTextMarshaling(t *testing.T) {
+	v := MustParse("v1.0.POST1")
+	text, err := v.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != "1.0.post1" {
+		t.Errorf("MarshalText = %q, want %q", text, "1.0.post1")
+	}
+	var got Version
+	if err := got.UnmarshalText([]byte("1.0-1")); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "1.0.post1" {
+		t.Errorf("UnmarshalText = %q, want %q", got, "1.0.post1")
+	}
+	if err := got.UnmarshalText([]byte("not a version")); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("UnmarshalText(invalid) = %v, want ErrInvalidVersion", err)
+	}
+
+	// Empty text is not a version; only null decodes to the zero Version.
+	var pe *ParseError
+	if err := got.UnmarshalText(nil); !errors.As(err, &pe) || pe.Reason != ReasonEmpty {
+		t.Errorf("UnmarshalText(\"\") = %v, want a ParseError with %s", err, ReasonEmpty)
+	}
+	var out config
+	if err := json.Unmarshal([]byte(`{"version":""}`), &out); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Unmarshal(empty version) = %v, want ErrInvalidVersion", err)
+	}
+	if err := got.Scan(""); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Scan(\"\") = %v, want ErrInvalidVersion", err)
+	}
+}
+
+func TestSQL(t *testing.T) {
+	v := MustParse("1.0a1")
+	val, err := v.Value()
+	if err != nil || val != "1.0a1" {
+		t.Errorf("Value() = %v, %v, want %q", val, err, "1.0a1")
+	}
+	if val, err := (Version{}).Value(); err != nil || val != nil {
+		t.Errorf("zero Value() = %v, %v, want nil", val, err)
+	}
+
+	for _, src := range []any{"1.0A1", []byte("1.0a1")} {
+		var got Version
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("Scan(%v): %v", src, err)
+		}
+		if got.String() != "1.0a1" {
+			t.Errorf("Scan(%v) = %q, want %q", src, got, "1.0a1")
+		}
+	}
+	got := MustParse("2.0")
+	if err := got.Scan(nil); err != nil || got.String() != "" {
+		t.Errorf("Scan(nil) = %q, %v, want zero Version", got, err)
+	}
+	if err := got.Scan(42); err == nil {
+		t.Error("Scan(int): expected an error")
+	}
+	if err := got.Scan("1.0..0"); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Scan(invalid) = %v, want ErrInvalidVersion", err)
+	}
+}
@@ -0,0 +1,190 @@
+package pyver
+
+import (
+	"cmp"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// numeral is a non-negative integer of arbitrary size. Values that fit in an
+// int live in n; larger ones keep n saturated at math.MaxInt and carry their
+// decimal digits (without leading zeros) in digits.
+type numeral struct {
+	n      int
+	digits string
+}
+
+// parseNumeral converts a string of ASCII digits into a numeral. Leading
+// zeros are ignored, as PEP 440 numerals are compared by value.
+func parseNumeral(s string) numeral {
+	t := strings.TrimLeft(s, "0")
+	if t == "" {
+		return numeral{}
+	}
+	if n, err := strconv.Atoi(t); err == nil {
+		return numeral{n: n}
+	}
+	return numeral{n: math.MaxInt, digits: t}
+}
+
+// String returns the decimal form of the numeral.
+func (a numeral) String() string {
+	if a.digits != "" {
+		return a.digits
+	}
+	return strconv.Itoa(a.n)
+}
+
+// compare orders two numerals by value.
+func (a numeral) compare(b numeral) int {
+	if a.digits == "" && b.digits == "" {
+		return cmp.Compare(a.n, b.n)
+	}
+	// At least one side exceeds math.MaxInt, so comparing the digit strings
+	// by length and then lexically gives the numeric order.
+	da, db := a.String(), b.String()
+	if c := cmp.Compare(len(da), len(db)); c != 0 {
+		return c
+	}
+	return strings.Compare(da, db)
+}
+
+// isZero reports whether the numeral is zero.
+func (a numeral) isZero() bool {
+	return a.n == 0 && a.digits == ""
+}
+
+// inc returns a + 1.
+func (a numeral) inc() numeral {
+	if a.digits == "" && a.n < math.MaxInt {
+		return numeral{n: a.n + 1}
+	}
+	d := []byte(a.String())
+	i := len(d) - 1
+	for ; i >= 0 && d[i] == '9'; i-- {
+		d[i] = '0'
+	}
+	if i < 0 {
+		d = append([]byte{'1'}, d...)
+	} else {
+		d[i]++
+	}
+	return numeral{n: math.MaxInt, digits: string(d)}
+}
+
+// wideNumerals holds the digits of numeric segments too large for an int.
+// A Version only allocates one when such a segment is present.
+type wideNumerals struct {
+	epoch   string
+	release []string // parallel to Version.Release; "" where the value fits
+	pre     string
+	post    string
+	dev     string
+}
+
+// wideOr returns the numeral for an int field, using the stored digits only
+// while the field still holds the saturated value they stand for.
+func wideOr(n int, digits string) numeral {
+	if digits != "" && n == math.MaxInt {
+		return numeral{n: n, digits: digits}
+	}
+	return numeral{n: n}
+}
+
+func (v *Version) wideNums() *wideNumerals {
+	if v.wide == nil {
+		v.wide = &wideNumerals{}
+	}
+	return v.wide
+}
+
+func (v Version) epochNumeral() numeral {
+	if v.wide == nil {
+		return numeral{n: v.Epoch}
+	}
+	return wideOr(v.Epoch, v.wide.epoch)
+}
+
+func (v Version) releaseNumeral(i int) numeral {
+	if v.wide == nil || i >= len(v.wide.release) {
+		return numeral{n: v.Release[i]}
+	}
+	return wideOr(v.Release[i], v.wide.release[i])
+}
+
+func (v Version) preNumeral() numeral {
+	if v.wide == nil {
+		return numeral{n: v.PreNum}
+	}
+	return wideOr(v.PreNum, v.wide.pre)
+}
+
+func (v Version) postNumeral() numeral {
+	if v.wide == nil {
+		return numeral{n: v.PostNum}
+	}
+	return wideOr(v.PostNum, v.wide.post)
+}
+
+func (v Version) devNumeral() numeral {
+	if v.wide == nil {
+		return numeral{n: v.DevNum}
+	}
+	return wideOr(v.DevNum, v.wide.dev)
+}
+
+func (v *Version) setEpoch(n numeral) {
+	v.Epoch = n.n
+	if n.digits != "" {
+		v.wideNums().epoch = n.digits
+	}
+}
+
+func (v *Version) appendRelease(n numeral) {
+	v.Release = append(v.Release, n.n)
+	if n.digits != "" {
+		w := v.wideNums()
+		for len(w.release) < len(v.Release)-1 {
+			w.release = append(w.release, "")
+		}
+		w.release = append(w.release, n.digits)
+	}
+}
+
+func (v *Version) setPre(kind string, n numeral) {
+	v.PreKind = kind
+	v.PreNum = n.n
+	if n.digits != "" {
+		v.wideNums().pre = n.digits
+	}
+}
+
+func (v *Version) setPost(n numeral) {
+	v.HasPost = true
+	v.PostNum = n.n
+	if n.digits != "" {
+		v.wideNums().post = n.digits
+	}
+}
+
+func (v *Version) setDev(n numeral) {
+	v.HasDev = true
+	v.DevNum = n.n
+	if n.digits != "" {
+		v.wideNums().dev = n.digits
+	}
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package pyver
 
 import (
+	"math"
 	"strconv"
 	"strings"
 	"testing"
@@ -21,15 +22,15 @@ func preToString(kind string, num int) string {
 	return kind + strconv.Itoa(num)
 }
 
-func postToString(num int) string {
-	if num == 0 {
+func postToString(has bool, num int) string {
+	if !has {
 		return ""
 	}
 	return "post" + strconv.Itoa(num)
 }
 
-func devToString(num int) string {
-	if num == 0 {
+func devToString(has bool, num int) string {
+	if !has {
 		return ""
 	}
 	return "dev" + strconv.Itoa(num)
@@ -53,6 +54,13 @@ func TestParsePEP440Fields(t *testing.T) {
 		{"dev", "1.0.0.dev4", 0, "1.0.0", "", "", "dev4", ""},
 		{"local", "1.0.0+abc.5", 0, "1.0.0", "", "", "", "abc.5"},
 		{"all fields", "2!3.4.5a1.post2.dev3+meta", 2, "3.4.5", "a1", "post2", "dev3", "meta"},
+		{"pre zero", "1.0a0", 0, "1.0", "a0", "", "", ""},
+		{"post zero", "1.0.post0", 0, "1.0", "", "post0", "", ""},
+		{"implicit post zero", "1.0-0", 0, "1.0", "", "post0", "", ""},
+		{"post no number", "1.0.post", 0, "1.0", "", "post0", "", ""},
+		{"dev zero", "1.0.dev0", 0, "1.0", "", "", "dev0", ""},
+		{"dev no number", "1.0dev", 0, "1.0", "", "", "dev0", ""},
+		{"multi-digit pre", "1.0rc12", 0, "1.0", "rc12", "", "", ""},
 	}
 
 	for _, tc := range tests {
@@ -71,11 +79,11 @@ func TestParsePEP440Fields(t *testing.T) {
 			if preToString(v.PreKind, v.PreNum) != tc.pre {
 				t.Errorf("pre: got %q, want %q", preToString(v.PreKind, v.PreNum), tc.pre)
 			}
-			if postToString(v.PostNum) != tc.post {
-				t.Errorf("post: got %q, want %q", postToString(v.PostNum), tc.post)
+			if postToString(v.HasPost, v.PostNum) != tc.post {
+				t.Errorf("post: got %q, want %q", postToString(v.HasPost, v.PostNum), tc.post)
 			}
-			if devToString(v.DevNum) != tc.dev {
-				t.Errorf("dev: got %q, want %q", devToString(v.DevNum), tc.dev)
+			if devToString(v.HasDev, v.DevNum) != tc.dev {
+				t.Errorf("dev: got %q, want %q", devToString(v.HasDev, v.DevNum), tc.dev)
 			}
 			if strings.Join(v.Local, ".") != tc.local {
 				t.Errorf("local: got %q, want %q", strings.Join(v.Local, "."), tc.local)
@@ -84,28 +92,30 @@ func TestParsePEP440Fields(t *testing.T) {
 	}
 }
 
+// validVersionCases are accepted by Parse in every lenient spelling.
+var validVersionCases = []string{
+	// Simple releases
+	"0.0.1", "1.0.0", "7.1.0", "2.2.3", "10.20.30",
+	// Pre-releases
+	"1.2.0rc1", "1.0.0a1", "1.0.0b2", "1.0.0rc3", "1.0.0a0", "1.0.0b0", "1.0.0rc0",
+	// Post-releases
+	"3.0.0.post1", "1.0.0.post2", "1.0.0-1", "1.0.0post1", "1.0.0rev1", "1.0.0r1", "1.0.0.post0", "1.0.0.post",
+	// Dev releases
+	"1.0.0.dev1", "1.0.0.dev0", "1.0.0dev2",
+	// Epochs
+	"1!1.0.0", "2!3.4.5a1.post2.dev3+meta",
+	// Local versions
+	"1.0.0+abc", "1.0.0+abc.5", "1.0.0+abc-def", "1.0.0+abc_def", "1.0.0+abc.def",
+	// Normalization and whitespace
+	"1.0.0-rc1", "1.0.0_rc1", "v1.0.0", " 1.0.0 ",
+	// Leading zeros
+	"01.2.3", "1.02.3", "1.2.03",
+	// Complex combos
+	"1!2.3.4a5.post6.dev7+abc.def",
+}
+
 func TestParsePEP440AllValidCases(t *testing.T) {
-	cases := []string{
-		// Simple releases
-		"0.0.1", "1.0.0", "7.1.0", "2.2.3", "10.20.30",
-		// Pre-releases
-		"1.2.0rc1", "1.0.0a1", "1.0.0b2", "1.0.0rc3", "1.0.0a0", "1.0.0b0", "1.0.0rc0",
-		// Post-releases
-		"3.0.0.post1", "1.0.0.post2", "1.0.0-1", "1.0.0post1", "1.0.0rev1", "1.0.0r1", "1.0.0.post0", "1.0.0.post",
-		// Dev releases
-		"1.0.0.dev1", "1.0.0.dev0", "1.0.0dev2",
-		// Epochs
-		"1!1.0.0", "2!3.4.5a1.post2.dev3+meta",
-		// Local versions
-		"1.0.0+abc", "1.0.0+abc.5", "1.0.0+abc-def", "1.0.0+abc_def", "1.0.0+abc.def",
-		// Normalization and whitespace
-		"1.0.0-rc1", "1.0.0_rc1", "v1.0.0", " 1.0.0 ",
-		// Leading zeros
-		"01.2.3", "1.02.3", "1.2.03",
-		// Complex combos
-		"1!2.3.4a5.post6.dev7+abc.def",
-	}
-	for _, input := range cases {
+	for _, input := range validVersionCases {
 		t.Run(input, func(t *testing.T) {
 			v, err := Parse(input)
 			if err != nil {
@@ -117,3 +127,21 @@ func TestParsePEP440AllValidCases(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLargeNumerals(t *testing.T) {
+	v, err := Parse("1.20240101123045123456789.3")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if v.Release[0] != 1 || v.Release[1] != math.MaxInt || v.Release[2] != 3 {
+		t.Errorf("release: got %v, want [1 %d 3]", v.Release, math.MaxInt)
+	}
+	if v.String() != "1.20240101123045123456789.3" {
+		t.Errorf("normalized: got %q", v.String())
+	}
+	// A field changed by the caller no longer refers to the stored digits
+	v.Release[1] = 5
+	if got := versionToString(v); got != "1.5.3" {
+		t.Errorf("after mutation: got %q, want %q", got, "1.5.3")
+	}
+}
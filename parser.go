@@ -0,0 +1,108 @@
+package pyver
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// discardLogger is the silent default for diagnostics.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+var packageLogger atomic.Pointer[slog.Logger]
+
+// SetLogger installs l as the package-wide destination for diagnostics,
+// such as backend invocations and rejected inputs. All messages are logged
+// at debug level. Passing nil restores the default, which discards them.
+func SetLogger(l *slog.Logger) {
+	packageLogger.Store(l)
+}
+
+// Parser parses and compares versions with per-instance settings. The zero
+// value, and a nil *Parser, behave like the package-level functions.
+type Parser struct {
+	// Backend implements parsing and comparison. If nil, DefaultBackend()
+	// is used.
+	Backend Backend
+
+	// Logger receives diagnostics for calls made through this Parser,
+	// including those of a Worker backend. If nil, the logger installed
+	// with SetLogger, or the Worker's own, is used.
+	Logger *slog.Logger
+}
+
+func (p *Parser) logger() *slog.Logger {
+	if p != nil && p.Logger != nil {
+		return p.Logger
+	}
+	if l := packageLogger.Load(); l != nil {
+		return l
+	}
+	return discardLogger
+}
+
+func (p *Parser) backend() Backend {
+	if p != nil && p.Backend != nil {
+		return p.Backend
+	}
+	return DefaultBackend()
+}
+
+// Parse parses a version string into a Version struct.
+func (p *Parser) Parse(s string) (Version, error) {
+	return p.ParseContext(context.Background(), s)
+}
+
+// ParseContext is like Parse but stops waiting for a backend that
+// implements ContextBackend, such as a Worker, when ctx is done, returning a
+// *CanceledError.
+func (p *Parser) ParseContext(ctx context.Context, s string) (Version, error) {
+	var v Version
+	var err error
+	b := p.backend()
+	if lb, ok := b.(loggingBackend); ok && p != nil && p.Logger != nil {
+		v, err = lb.parseLogged(ctx, p.Logger, s)
+	} else if cb, ok := b.(ContextBackend); ok {
+		v, err = cb.ParseContext(ctx, s)
+	} else {
+		v, err = b.Parse(s)
+	}
+	if err != nil {
+		p.logger().Debug("pyver: parse failed", "input", s, "error", err)
+	}
+	return v, err
+}
+
+// Compare returns -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2. It panics if
+// the backend fails.
+func (p *Parser) Compare(v1, v2 Version) int {
+	c, err := p.TryCompare(v1, v2)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// TryCompare is like Compare but returns backend failures as errors.
+func (p *Parser) TryCompare(v1, v2 Version) (int, error) {
+	return p.CompareContext(context.Background(), v1, v2)
+}
+
+// CompareContext is like TryCompare but stops waiting for a backend that
+// implements ContextBackend when ctx is done, returning a *CanceledError.
+func (p *Parser) CompareContext(ctx context.Context, v1, v2 Version) (int, error) {
+	var c int
+	var err error
+	b := p.backend()
+	if lb, ok := b.(loggingBackend); ok && p != nil && p.Logger != nil {
+		c, err = lb.compareLogged(ctx, p.Logger, v1, v2)
+	} else if cb, ok := b.(ContextBackend); ok {
+		c, err = cb.CompareContext(ctx, v1, v2)
+	} else {
+		c, err = b.Compare(v1, v2)
+	}
+	if err != nil {
+		p.logger().Debug("pyver: compare failed", "v1", v1.Original, "v2", v2.Original, "error", err)
+	}
+	return c, err
+}
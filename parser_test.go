@@ -0,0 +1,99 @@
+package pyver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func debugLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestParseSilentByDefault(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stderr
+	os.Stderr = w
+	for _, s := range []string{"1.0rc1", "1.0preview2", "1..0"} {
+		Parse(s)
+	}
+	os.Stderr = orig
+	w.Close()
+	out, _ := io.ReadAll(r)
+	if len(out) != 0 {
+		t.Errorf("unexpected stderr output: %q", out)
+	}
+}
+
+func TestParserLogger(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Parser{Backend: NativeBackend{}, Logger: debugLogger(&buf)}
+	if _, err := p.Parse("1..0"); err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(buf.String(), "pyver: parse failed") || !strings.Contains(buf.String(), `input=1..0`) {
+		t.Errorf("missing diagnostic, got %q", buf.String())
+	}
+	buf.Reset()
+	if _, err := p.Parse("1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected diagnostic for valid input: %q", buf.String())
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogger(debugLogger(&buf))
+	t.Cleanup(func() { SetLogger(nil) })
+	Parse("1.0+")
+	if !strings.Contains(buf.String(), "pyver: parse failed") {
+		t.Errorf("missing diagnostic, got %q", buf.String())
+	}
+
+	// A Parser's own logger takes precedence over the package logger
+	var own bytes.Buffer
+	buf.Reset()
+	(&Parser{Logger: debugLogger(&own)}).Parse("1.0+")
+	if buf.Len() != 0 || own.Len() == 0 {
+		t.Errorf("package logger got %q, parser logger got %q", buf.String(), own.String())
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The native backend answers at once and ignores the context.
+	native := &Parser{Backend: NativeBackend{}}
+	if v, err := native.ParseContext(ctx, "1.0"); err != nil || v.Normalized != "1.0" {
+		t.Errorf("ParseContext = %q, %v", v.Normalized, err)
+	}
+
+	v1, v2 := MustParse("1.0"), MustParse("2.0")
+	var buf bytes.Buffer
+	w := &Worker{}
+	defer w.Close()
+	useBackend(t, w)
+	for _, p := range []*Parser{nil, {Logger: debugLogger(&buf)}} {
+		var ce *CanceledError
+		if _, err := p.ParseContext(ctx, "1.0"); !errors.As(err, &ce) || !errors.Is(err, context.Canceled) {
+			t.Errorf("ParseContext = %v, want a CanceledError", err)
+		}
+		if _, err := p.CompareContext(ctx, v1, v2); !errors.As(err, &ce) || ce.Op != "compare" {
+			t.Errorf("CompareContext = %v, want a CanceledError", err)
+		}
+	}
+	if !strings.Contains(buf.String(), "pyver: parse failed") {
+		t.Errorf("missing diagnostic, got %q", buf.String())
+	}
+}
@@ -0,0 +1,202 @@
+package pyver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidPoetryConstraint is matched by the errors ParsePoetryConstraint
+// returns.
+var ErrInvalidPoetryConstraint = errors.New("invalid Poetry constraint")
+
+// ErrNoPEP440Equivalent is returned by PoetryConstraint.SpecifierSet when no
+// specifier set contains exactly the versions the constraint does.
+var ErrNoPEP440Equivalent = errors.New("no equivalent PEP 440 specifier set")
+
+// PoetryConstraint is a version constraint in the syntax of Poetry's
+// [tool.poetry.dependencies], such as "^1.2", "~1.2.3", "1.2.*" or
+// ">=1.0 || <0.5". Each alternative separated by "||" is a SpecifierSet.
+type PoetryConstraint struct {
+	raw  string
+	alts []SpecifierSet
+}
+
+// ParsePoetryConstraint parses a Poetry constraint: alternatives separated
+// by "||" (or "|"), each a list of clauses separated by commas or
+// whitespace. A clause is translated to PEP 440 as Poetry defines it:
+//
+//   - ^V allows changes that keep the leftmost non-zero component of V's
+//     first three: "^1.2.3" -> ">=1.2.3,<2.0.0", "^0.2.3" ->
+//     ">=0.2.3,<0.3.0", "^0.0" -> ">=0.0,<0.1".
+//   - ~V allows patch changes, or minor ones if V has a single component:
+//     "~1.2.3" -> ">=1.2.3,<1.3.0", "~1" -> ">=1,<2".
+//   - "*" matches any version, and V.*, V.x or V.X is "==V.*".
+//   - A bare version or =V is "==V", and <>V is "!=V".
+//   - Any other clause is a PEP 440 specifier such as ">=1.0" or "~=1.4.2".
+func ParsePoetryConstraint(s string) (PoetryConstraint, error) {
+	fail := func(reason string) (PoetryConstraint, error) {
+		return PoetryConstraint{}, fmt.Errorf("%w %q: %s", ErrInvalidPoetryConstraint, s, reason)
+	}
+	c := PoetryConstraint{raw: strings.TrimFunc(s, isSpace)}
+	if c.raw == "" {
+		return fail("expected a constraint")
+	}
+	for alt := range strings.SplitSeq(strings.ReplaceAll(c.raw, "||", "|"), "|") {
+		clauses := poetryClauses(alt)
+		if len(clauses) == 0 {
+			return fail("empty alternative")
+		}
+		var set SpecifierSet
+		for _, clause := range clauses {
+			specs, err := poetrySpecifiers(clause)
+			if err != nil {
+				return PoetryConstraint{}, fmt.Errorf("%w %q: %w", ErrInvalidPoetryConstraint, s, err)
+			}
+			for _, sp := range specs {
+				set.add(sp)
+			}
+		}
+		c.alts = append(c.alts, set)
+	}
+	return c, nil
+}
+
+// MustParsePoetryConstraint parses a Poetry constraint or panics.
+func MustParsePoetryConstraint(s string) PoetryConstraint {
+	c, err := ParsePoetryConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// poetryClauses splits an alternative into clauses at commas and
+// whitespace, keeping an operator written apart with its version:
+// ">= 1.2, < 1.5" -> [">=1.2" "<1.5"].
+func poetryClauses(alt string) []string {
+	var out []string
+	pending := ""
+	for _, f := range strings.FieldsFunc(alt, func(r rune) bool { return r == ',' || isSpace(r) }) {
+		if strings.Trim(f, "^~=<>!") == "" {
+			pending += f
+			continue
+		}
+		out = append(out, pending+f)
+		pending = ""
+	}
+	if pending != "" {
+		out = append(out, pending)
+	}
+	return out
+}
+
+// poetrySpecifiers translates one Poetry clause to PEP 440 specifiers.
+func poetrySpecifiers(clause string) ([]Specifier, error) {
+	if clause == "*" || clause == "x" || clause == "X" {
+		return nil, nil
+	}
+	op, ver := "", clause
+	for _, o := range []string{"==", "!=", "<>", "~=", "^", "~", "="} {
+		if rest, ok := strings.CutPrefix(clause, o); ok {
+			op, ver = o, rest
+			break
+		}
+	}
+	switch op {
+	case "^", "~":
+		v, err := parseGoNative(ver)
+		if err != nil {
+			return nil, err
+		}
+		i := 0
+		if op == "^" {
+			for i < min(len(v.Release), 3)-1 && v.releaseNumeral(i).isZero() {
+				i++
+			}
+		} else if len(v.Release) > 1 {
+			i = 1
+		}
+		return parseSpecifiers(">="+v.String(), "<"+v.BumpRelease(i).String())
+	case "":
+		if strings.HasPrefix(clause, "<") || strings.HasPrefix(clause, ">") {
+			return parseSpecifiers(clause)
+		}
+		fallthrough
+	case "=", "==", "!=", "<>":
+		if op == "!=" || op == "<>" {
+			op = "!="
+		} else {
+			op = "=="
+		}
+		for _, w := range []string{".*", ".x", ".X"} {
+			if prefix, ok := strings.CutSuffix(ver, w); ok {
+				ver = strings.TrimRight(prefix, ".*xX") + ".*"
+				break
+			}
+		}
+		return parseSpecifiers(op + ver)
+	}
+	return parseSpecifiers(clause)
+}
+
+func parseSpecifiers(clauses ...string) ([]Specifier, error) {
+	specs := make([]Specifier, len(clauses))
+	for i, s := range clauses {
+		sp, err := ParseSpecifier(s)
+		if err != nil {
+			return nil, err
+		}
+		specs[i] = sp
+	}
+	return specs, nil
+}
+
+// String returns the constraint as given, without surrounding whitespace.
+func (c PoetryConstraint) String() string {
+	return c.raw
+}
+
+// Alternatives returns the PEP 440 translation of each alternative of c.
+func (c PoetryConstraint) Alternatives() []SpecifierSet {
+	return slices.Clone(c.alts)
+}
+
+// Contains reports whether any alternative of c contains v. Pre-releases
+// follow the rules of SpecifierSet.Contains: an alternative only admits
+// them if one of its clauses mentions a pre-release.
+func (c PoetryConstraint) Contains(v Version) bool {
+	return slices.ContainsFunc(c.alts, func(s SpecifierSet) bool { return s.Contains(v) })
+}
+
+// SpecifierSet returns a PEP 440 specifier set containing exactly the
+// versions c contains, for rewriting a constraint into
+// [project.dependencies]. A constraint without "||" translates directly;
+// alternatives are joined with SpecifierSet.Union, and when that has no
+// exact equivalent, as for ">=1.0 || <0.5", the error matches
+// ErrNoPEP440Equivalent:
+//
+//	"^1.2" -> "<2.0,>=1.2"
+//	"^1.0 || ^2.0" -> "<3.0,>=1.0"
+func (c PoetryConstraint) SpecifierSet() (SpecifierSet, error) {
+	if len(c.alts) == 1 {
+		return c.alts[0], nil
+	}
+	for _, s := range c.alts {
+		if !slices.ContainsFunc(c.alts, func(o SpecifierSet) bool { return !o.IsSubsetOf(s) }) {
+			return s, nil
+		}
+	}
+	// A === clause has no region to rebuild a union from.
+	if !slices.ContainsFunc(c.alts, SpecifierSet.hasArbitrary) {
+		r := c.alts[0].region()
+		for _, s := range c.alts[1:] {
+			r = r.union(s.region())
+		}
+		if s, ok := describe(r, true); ok {
+			return s, nil
+		}
+	}
+	return SpecifierSet{}, fmt.Errorf("%w: %q", ErrNoPEP440Equivalent, c.raw)
+}
@@ -0,0 +1,116 @@
+package pyver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePoetryConstraint(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"^1.2.3", "<2.0.0,>=1.2.3"},
+		{"^0.2.3", "<0.3.0,>=0.2.3"},
+		{"^0.0.3", "<0.0.4,>=0.0.3"},
+		{"^0.0", "<0.1,>=0.0"},
+		{"^0", "<1,>=0"},
+		{"^1.2.3.4", "<2.0.0.0,>=1.2.3.4"},
+		{"^0.0.0.4", "<0.0.1.0,>=0.0.0.4"},
+		{"^2.0.0a1", "<3.0.0,>=2.0.0a1"},
+		{"^1!1.2", "<1!2.0,>=1!1.2"},
+		{"~1.2.3", "<1.3.0,>=1.2.3"},
+		{"~1.2", "<1.3,>=1.2"},
+		{"~1", "<2,>=1"},
+		{"~=1.4.2", "~=1.4.2"},
+		{"1.2.*", "==1.2.*"},
+		{"1.x", "==1.*"},
+		{"==1.2.X", "==1.2.*"},
+		{"!=1.2.*", "!=1.2.*"},
+		{"*", ""},
+		{"1.2.3", "==1.2.3"},
+		{"v1.2", "==v1.2"},
+		{"=1.2", "==1.2"},
+		{"<>1.0", "!=1.0"},
+		{">= 1.2, < 1.5", "<1.5,>=1.2"},
+		{">=1.2 <1.5", "<1.5,>=1.2"},
+		{"^ 1.2", "<2.0,>=1.2"},
+		{"^1.0 || ^2.0", "<3.0,>=1.0"},
+		{"^1.2 | ^1.4", "<2.0,>=1.2"},
+		{"^1.0 || ^3.0", "!=2.*,<4.0,>=1.0"},
+		{"==1.0 || *", ""},
+		{"===1.0 || ==1.0", "==1.0"},
+	}
+	for _, tc := range tests {
+		c, err := ParsePoetryConstraint(tc.input)
+		if err != nil {
+			t.Errorf("ParsePoetryConstraint(%q): %v", tc.input, err)
+			continue
+		}
+		set, err := c.SpecifierSet()
+		if err != nil || set.String() != tc.want {
+			t.Errorf("ParsePoetryConstraint(%q).SpecifierSet() = %q, %v, want %q", tc.input, set, err, tc.want)
+		}
+	}
+	for _, s := range []string{"", " ", "^", "^1.0 ||", "||", "lolwat", "^1.0,,~", ">=1.0.*", "1.0 <"} {
+		if c, err := ParsePoetryConstraint(s); !errors.Is(err, ErrInvalidPoetryConstraint) {
+			t.Errorf("ParsePoetryConstraint(%q) = %q, %v, want ErrInvalidPoetryConstraint", s, c, err)
+		}
+	}
+}
+
+func TestPoetryConstraintNoEquivalent(t *testing.T) {
+	for _, s := range []string{">=1.0 || <0.5", "==1.0 || ==2.0", "~1.0 || ~1.5", "==2.0 || ===1.0+abc"} {
+		c := MustParsePoetryConstraint(s)
+		if set, err := c.SpecifierSet(); !errors.Is(err, ErrNoPEP440Equivalent) {
+			t.Errorf("%q.SpecifierSet() = %q, %v, want ErrNoPEP440Equivalent", s, set, err)
+		}
+		if len(c.Alternatives()) != 2 {
+			t.Errorf("%q has %d alternatives, want 2", s, len(c.Alternatives()))
+		}
+	}
+}
+
+func TestPoetryConstraintContains(t *testing.T) {
+	tests := []struct {
+		constraint, version string
+		want                bool
+	}{
+		{"^1.2", "1.9.9", true},
+		{"^1.2", "2.0", false},
+		{"^1.2", "2.0rc1", false},
+		{"^1.2", "1.5a1", false},
+		{"^1.2a1", "1.5a1", true},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3", false},
+		{">=1.0 || <0.5", "0.4", true},
+		{">=1.0 || <0.5", "0.7", false},
+		{">=1.0 || <0.5", "1.0", true},
+		{"1.2.*", "1.2.post1", true},
+		{"*", "3.0", true},
+	}
+	for _, tc := range tests {
+		if got := MustParsePoetryConstraint(tc.constraint).Contains(MustParse(tc.version)); got != tc.want {
+			t.Errorf("%q.Contains(%q) = %v, want %v", tc.constraint, tc.version, got, tc.want)
+		}
+	}
+}
+
+// TestPoetryConstraintSpecifierSet checks that translated sets contain
+// exactly the versions the constraints do.
+func TestPoetryConstraintSpecifierSet(t *testing.T) {
+	vs := regionVersions()
+	for _, a := range []string{"^1.0", "~1.0", "^1.0rc1", "1.*", "<1.0", "^2.0", ">=1.0.post1", "!=1.0.*"} {
+		for _, b := range []string{"^1.0", "~1.0.0", "^2.0", "==1.0", ">1.0", "<1.0a1", "1!1.*"} {
+			c := MustParsePoetryConstraint(a + " || " + b)
+			set, err := c.SpecifierSet()
+			if err != nil {
+				continue
+			}
+			for _, v := range vs {
+				if set.Contains(v) != c.Contains(v) {
+					t.Errorf("%q.SpecifierSet() = %q (%v), which differs on %q", c, set, set.PreReleasePolicy(), v)
+				}
+			}
+		}
+	}
+}
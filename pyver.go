@@ -2,44 +2,27 @@ package pyver
 
 import (
 	"bytes"
+	"cmp"
+	"context"
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
-	"regexp"
-	"runtime"
 	"strconv"
 	"strings"
 	"unicode"
 )
 
-// BackendPath is the absolute path to the backend script.
-var BackendPath string
-
-// UseGoNative toggles between the Python backend and Go-native implementation.
-// Set to true to use Go-native parsing/comparison (in development).
-var UseGoNative = true
+// backendScript is pyver_backend.py, run with "python3 -c" so that the
+// backend works without the module source on disk.
+//
+//go:embed pyver_backend.py
+var backendScript string
 
-func init() {
-	// Use runtime.Caller to get the directory of this source file
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		panic("could not determine pyver.go location via runtime.Caller")
-	}
-	backend := filepath.Join(filepath.Dir(filename), "pyver_backend.py")
-	if _, err := os.Stat(backend); err == nil {
-		BackendPath = backend
-		return
-	}
-	// fallback: look one directory up (for monorepo or test layouts)
-	backend = filepath.Join(filepath.Dir(filepath.Dir(filename)), "pyver", "pyver_backend.py")
-	if _, err := os.Stat(backend); err == nil {
-		BackendPath = backend
-		return
-	}
-	panic("pyver_backend.py not found relative to pyver.go")
-}
+// BackendPath optionally names a backend script to run instead of the
+// embedded copy of pyver_backend.py, e.g. while working on the script.
+var BackendPath string
 
 // pyver.go: Go interface to Python PEP 440 version parsing/comparison.
 //
@@ -68,30 +51,37 @@ func getPythonArgs() []string {
 
 // Parse parses a version string into a Version struct.
 func Parse(s string) (Version, error) {
-	if UseGoNative {
-		return parseGoNative(s)
-	}
+	return (*Parser)(nil).Parse(s)
+}
+
+// ParseContext is like Parse but bounds a Python backend call by ctx: if
+// ctx is done before the backend answers, it returns a *CanceledError.
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Second)
+//	defer cancel()
+//	v, err := pyver.ParseContext(ctx, s)
+func ParseContext(ctx context.Context, s string) (Version, error) {
+	return (*Parser)(nil).ParseContext(ctx, s)
+}
+
+// decodeBackendVersion builds the Version for s from the backend's
+// description of it.
+func decodeBackendVersion(s string, out []byte) (Version, error) {
 	v := Version{Original: s}
-	args := append(getPythonArgs(), BackendPath, "parse", s)
-	cmd := exec.Command(args[0], args[1:]...)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	out, err := cmd.Output()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[pyver debug] Parse failed for input: %q\n  Command: %v\n  Stderr: %s\n  Error: %v\n", s, args, stderr.String(), err)
-		return v, fmt.Errorf("pyver backend error: %v\nCommand: %v\nStderr: %s", err, args, stderr.String())
-	}
+	// Decode numbers as json.Number so numerals of any size survive intact
 	var resp map[string]any
-	if err := json.Unmarshal(out, &resp); err != nil {
-		return v, fmt.Errorf("pyver backend JSON error: %v", err)
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+	if err := dec.Decode(&resp); err != nil {
+		return v, fmt.Errorf("%w: invalid JSON: %v", ErrBackend, err)
 	}
-	if epoch, ok := resp["epoch"].(float64); ok {
-		v.Epoch = int(epoch)
+	if epoch, ok := resp["epoch"].(json.Number); ok {
+		v.setEpoch(parseNumeral(epoch.String()))
 	}
 	if rel, ok := resp["release"].([]any); ok {
 		for _, n := range rel {
-			if f, ok := n.(float64); ok {
-				v.Release = append(v.Release, int(f))
+			if num, ok := n.(json.Number); ok {
+				v.appendRelease(parseNumeral(num.String()))
 			}
 		}
 	}
@@ -100,40 +90,59 @@ func Parse(s string) (Version, error) {
 	}
 	if pre, ok := resp["pre"].(string); ok && pre != "" {
 		// e.g. "a1", "b2", "rc3"
-		if len(pre) >= 2 {
-			v.PreKind = pre[:len(pre)-1]
-			if n, err := strconv.Atoi(pre[len(pre)-1:]); err == nil {
-				v.PreNum = n
-			}
-		} else if len(pre) == 1 {
-			v.PreKind = pre
-			v.PreNum = 0
+		i := strings.IndexFunc(pre, unicode.IsDigit)
+		if i < 0 {
+			i = len(pre)
 		}
+		v.setPre(pre[:i], parseNumeral(pre[i:]))
 	}
 	if post, ok := resp["post"].(string); ok && post != "" {
 		// e.g. "post2"
 		if strings.HasPrefix(post, "post") {
-			num := post[4:]
-			if n, err := strconv.Atoi(num); err == nil {
-				v.PostNum = n
-			}
+			v.setPost(parseNumeral(post[4:]))
 		}
 	}
 	if dev, ok := resp["dev"].(string); ok && dev != "" {
 		// e.g. "dev3"
 		if strings.HasPrefix(dev, "dev") {
-			num := dev[3:]
-			if n, err := strconv.Atoi(num); err == nil {
-				v.DevNum = n
-			}
+			v.setDev(parseNumeral(dev[3:]))
 		}
 	}
 	if local, ok := resp["local"].(string); ok && local != "" {
 		v.Local = strings.Split(local, ".")
 	}
+	if err := verifyAccessors(v, resp); err != nil {
+		return v, err
+	}
 	return v, nil
 }
 
+// verifyAccessors checks the convenience accessors of v against the
+// values packaging reported for the same version.
+func verifyAccessors(v Version, resp map[string]any) error {
+	want := map[string]any{
+		"public":         v.Public(),
+		"base_version":   v.BaseVersion(),
+		"is_prerelease":  v.IsPrerelease(),
+		"is_postrelease": v.IsPostrelease(),
+		"is_devrelease":  v.IsDevrelease(),
+		"major":          json.Number(strconv.Itoa(v.Major())),
+		"minor":          json.Number(strconv.Itoa(v.Minor())),
+		"micro":          json.Number(strconv.Itoa(v.Micro())),
+		"canonical":      v.Canonical(),
+	}
+	for key, w := range want {
+		got, ok := resp[key]
+		if n, isNum := got.(json.Number); isNum {
+			got = json.Number(strconv.Itoa(parseNumeral(n.String()).n))
+		}
+		if !ok || got != w {
+			return fmt.Errorf("%w: %s of %q is %v in packaging but %v in pyver", ErrBackend, key, v.Original, got, w)
+		}
+	}
+	return nil
+}
+
 // MustParse parses a version string or panics.
 func MustParse(s string) Version {
 	v, err := Parse(s)
@@ -143,24 +152,25 @@ func MustParse(s string) Version {
 	return v
 }
 
-// Compare returns -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2.
+// Compare returns -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2. Its
+// signature suits slices.SortFunc and similar helpers.
+//
+// Compare panics if the Python backend fails; use TryCompare to handle
+// backend errors.
 func Compare(v1, v2 Version) int {
-	if UseGoNative {
-		return compareGoNative(v1, v2)
-	}
-	args := append(getPythonArgs(), BackendPath, "compare", v1.Original, v2.Original)
-	cmd := exec.Command(args[0], args[1:]...)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	out, err := cmd.Output()
-	if err != nil {
-		panic(fmt.Errorf("pyver backend error: %v\nCommand: %v\nStderr: %s", err, args, stderr.String()))
-	}
-	cmp, err := strconv.Atoi(strings.TrimSpace(string(out)))
-	if err != nil {
-		panic(fmt.Errorf("pyver backend output error: %v", err))
-	}
-	return cmp
+	return (*Parser)(nil).Compare(v1, v2)
+}
+
+// TryCompare is like Compare but returns an error instead of panicking
+// when the Python backend fails. The Go-native implementation never fails.
+func TryCompare(v1, v2 Version) (int, error) {
+	return (*Parser)(nil).TryCompare(v1, v2)
+}
+
+// CompareContext is like TryCompare but bounds a Python backend call by
+// ctx, returning a *CanceledError if ctx is done first.
+func CompareContext(ctx context.Context, v1, v2 Version) (int, error) {
+	return (*Parser)(nil).CompareContext(ctx, v1, v2)
 }
 
 // String returns the normalized version string.
@@ -174,199 +184,47 @@ func (v Version) String() string {
 // --- Go-native PEP 440 parser and normalizer ---
 
 // parseGoNative parses and normalizes a PEP 440 version string in pure Go.
+// The scanner reads the input in a single pass; an input already in
+// normalized form is reused as the Normalized string.
 func parseGoNative(s string) (Version, error) {
-	orig := s
-	s = strings.TrimSpace(s)
-	s = strings.ToLower(s)
-	if strings.HasPrefix(s, "v") {
-		s = s[1:]
-	}
-
-	// Regex for PEP 440 (per Appendix B, with normalization flexibility)
-	var pep440Pattern = regexp.MustCompile(`^((?P<epoch>[0-9]+)!)?(?P<release>[0-9]+(?:\.[0-9]+)*)(?P<pre>([-_\.]?(preview|alpha|beta|rc|pre|c|a|b)[-_\.]?[0-9]*)?)?(?P<post>(-(?P<post_n1>[0-9]+))|(([-_\.]?(post|rev|r)[-_\.]?[0-9]*)))?(?P<dev>([-_\.]?dev[-_\.]?[0-9]*))?(\+(?P<local>[a-z0-9]+(?:[-_\.][a-z0-9]+)*))?$`)
-
-	m := pep440Pattern.FindStringSubmatch(s)
-	if m == nil {
-		return Version{Original: orig}, fmt.Errorf("invalid version: %q", orig)
-	}
-
-	v := Version{Original: orig}
-	group := func(name string) string {
-		for i, n := range pep440Pattern.SubexpNames() {
-			if n == name {
-				return m[i]
-			}
-		}
-		return ""
-	}
-
-	// Epoch
-	if e := group("epoch"); e != "" {
-		v.Epoch, _ = strconv.Atoi(e)
-	}
-
-	// Release
-	rel := group("release")
-	for _, part := range strings.Split(rel, ".") {
-		if part == "" {
-			return v, fmt.Errorf("invalid release segment: %q", rel)
-		}
-		n, err := strconv.Atoi(part)
-		if err != nil || n < 0 {
-			return v, fmt.Errorf("invalid release segment: %q", rel)
-		}
-		v.Release = append(v.Release, n)
-	}
-
-	// Pre-release
-	pre := group("pre")
-	if strings.Contains(orig, "rc") || strings.Contains(orig, "preview") {
-		fmt.Fprintf(os.Stderr, "[pyver debug] input=%q raw pre=%q\n", orig, pre)
-	}
-	if pre != "" {
-		// Normalize spelling and separator
-		pre = strings.ReplaceAll(pre, "_", "")
-		pre = strings.ReplaceAll(pre, "-", "")
-		pre = strings.ReplaceAll(pre, ".", "")
-		var kind string
-		var num int
-		for _, k := range []struct{ alt, norm string }{
-			{"preview", "rc"},
-			{"alpha", "a"}, {"a", "a"},
-			{"beta", "b"}, {"b", "b"},
-			{"pre", "rc"},
-			{"rc", "rc"}, {"c", "rc"},
-		} {
-			if strings.HasPrefix(pre, k.alt) {
-				kind = k.norm
-				pre = pre[len(k.alt):]
-				break
-			}
-		}
-		if kind != "" {
-			preNumStr := pre
-			if strings.Contains(orig, "rc") || strings.Contains(orig, "preview") {
-				fmt.Fprintf(os.Stderr, "[pyver debug] input=%q preNumStr=%q\n", orig, preNumStr)
-			}
-			if preNumStr == "" {
-				num = 0
-			} else {
-				num, _ = strconv.Atoi(preNumStr)
-			}
-			v.PreKind = kind
-			v.PreNum = num
-			if strings.Contains(orig, "rc") || strings.Contains(orig, "preview") {
-				fmt.Fprintf(os.Stderr, "[pyver debug] input=%q PreKind=%q PreNum=%d\n", orig, v.PreKind, v.PreNum)
-			}
-		}
-	}
-
-	// Post-release
-	post := group("post")
-	if post != "" {
-		// Normalize spelling and separator
-		post = strings.ReplaceAll(post, "_", "")
-		post = strings.ReplaceAll(post, "-", "")
-		post = strings.ReplaceAll(post, ".", "")
-		if strings.HasPrefix(post, "post") {
-			post = post[4:]
-		} else if strings.HasPrefix(post, "rev") {
-			post = post[3:]
-		} else if strings.HasPrefix(post, "r") {
-			post = post[1:]
-		}
-		if post == "" {
-			v.PostNum = 0
-		} else {
-			v.PostNum, _ = strconv.Atoi(post)
-		}
+	sc := scanner{in: s, seg: SegmentRelease}
+	if err := sc.scan(); err != nil {
+		return Version{Original: s}, err
 	}
-	if n := group("post_n1"); n != "" {
-		v.PostNum, _ = strconv.Atoi(n)
+	v := sc.v
+	v.Original = s
+	if sc.norms == 0 {
+		v.Normalized = s
+	} else {
+		v.Normalized = versionToString(v)
 	}
-
-	// Dev-release
-	dev := group("dev")
-	if dev != "" {
-		dev = strings.ReplaceAll(dev, "_", "")
-		dev = strings.ReplaceAll(dev, "-", "")
-		dev = strings.ReplaceAll(dev, ".", "")
-		if strings.HasPrefix(dev, "dev") {
-			dev = dev[3:]
-		}
-		if dev == "" {
-			v.DevNum = 0
-		} else {
-			v.DevNum, _ = strconv.Atoi(dev)
-		}
-	}
-
-	// Local version
-	local := group("local")
-	if local != "" {
-		// Normalize separators to '.'
-		for _, sep := range []string{"-", "_"} {
-			local = strings.ReplaceAll(local, sep, ".")
-		}
-		parts := strings.Split(local, ".")
-		for _, part := range parts {
-			if part == "" {
-				return v, fmt.Errorf("invalid local segment: %q", local)
-			}
-			// Must be alphanumeric
-			for _, r := range part {
-				if !unicode.IsDigit(r) && !unicode.IsLetter(r) {
-					return v, fmt.Errorf("invalid local segment: %q", local)
-				}
-			}
-			v.Local = append(v.Local, part)
-		}
-		// Must start and end with alphanumeric
-		if len(v.Local) == 0 || !isAlnum(v.Local[0]) || !isAlnum(v.Local[len(v.Local)-1]) {
-			return v, fmt.Errorf("invalid local segment: %q", local)
-		}
-	}
-
-	// Normalized string
-	v.Normalized = versionToString(v)
 	return v, nil
 }
 
-func isAlnum(s string) bool {
-	for _, r := range s {
-		if unicode.IsLetter(r) || unicode.IsDigit(r) {
-			return true
-		}
-	}
-	return false
-}
-
 // versionToString returns the canonical normalized version string.
 func versionToString(v Version) string {
 	var b strings.Builder
 	if v.Epoch > 0 {
-		b.WriteString(strconv.Itoa(v.Epoch))
+		b.WriteString(v.epochNumeral().String())
 		b.WriteString("!")
 	}
-	for i, n := range v.Release {
+	for i := range v.Release {
 		if i > 0 {
 			b.WriteString(".")
 		}
-		b.WriteString(strconv.Itoa(n))
+		b.WriteString(v.releaseNumeral(i).String())
 	}
 	if v.PreKind != "" {
 		b.WriteString(v.PreKind)
-		b.WriteString(strconv.Itoa(v.PreNum))
+		b.WriteString(v.preNumeral().String())
 	}
-	// Only include .postN if N > 0
-	if v.PostNum > 0 {
+	if v.HasPost {
 		b.WriteString(".post")
-		b.WriteString(strconv.Itoa(v.PostNum))
+		b.WriteString(v.postNumeral().String())
 	}
-	// Only include .devN if N > 0
-	if v.DevNum > 0 {
+	if v.HasDev {
 		b.WriteString(".dev")
-		b.WriteString(strconv.Itoa(v.DevNum))
+		b.WriteString(v.devNumeral().String())
 	}
 	if len(v.Local) > 0 {
 		b.WriteString("+")
@@ -376,123 +234,119 @@ func versionToString(v Version) string {
 }
 
 // --- Go-native PEP 440 comparison logic ---
-func compareGoNative(v1, v2 Version) int {
-	// 1. Compare epoch
-	if v1.Epoch != v2.Epoch {
-		if v1.Epoch < v2.Epoch {
-			return -1
-		}
-		return 1
-	}
-	// 2. Compare release (pad with zeros)
-	r1, r2 := v1.Release, v2.Release
-	maxLen := len(r1)
-	if len(r2) > maxLen {
-		maxLen = len(r2)
-	}
-	for i := 0; i < maxLen; i++ {
-		var n1, n2 int
-		if i < len(r1) {
-			n1 = r1[i]
-		}
-		if i < len(r2) {
-			n2 = r2[i]
-		}
-		if n1 != n2 {
-			if n1 < n2 {
-				return -1
-			}
-			return 1
-		}
-	}
 
-	// 3. Pre/dev/post/final ordering per PEP 440
-	// Helper: return a tuple (phase, prekind, pren, postn, devn) for comparison
-	// phase: 0=dev, 1=pre, 2=final, 3=post
-	phase := func(v Version) int {
-		if v.DevNum > 0 {
-			return 0 // dev
-		}
-		if v.PreKind != "" {
-			return 1 // pre
-		}
-		if v.PostNum > 0 {
-			return 3 // post
-		}
-		return 2 // final
+// Sentinel ranks used in the comparison key, mirroring packaging's
+// NegativeInfinity and Infinity objects. A finite segment sorts between them.
+const (
+	negInf = -1
+	finite = 0
+	posInf = 1
+)
+
+// preKindOrder ranks the normalized pre-release kinds.
+var preKindOrder = map[string]int{"a": 0, "b": 1, "rc": 2}
+
+// compareGoNative orders two versions by their PEP 440 comparison key:
+// (epoch, release with trailing zeros stripped, pre, post, dev, local).
+//
+// The pre, post and dev parts use the sentinels of packaging.version:
+//   - pre is -inf for a dev-only release (1.0.dev0 < 1.0a0), +inf when absent
+//   - post is -inf when absent
+//   - dev is +inf when absent
+//   - local is -inf when absent
+func compareGoNative(v1, v2 Version) int {
+	if c := v1.epochNumeral().compare(v2.epochNumeral()); c != 0 {
+		return c
 	}
-	ph1, ph2 := phase(v1), phase(v2)
-	if ph1 != ph2 {
-		if ph1 < ph2 {
-			return -1
-		}
-		return 1
+	if c := compareRelease(v1, v2); c != 0 {
+		return c
 	}
-	// If both are dev, compare dev numbers
-	if ph1 == 0 {
-		if v1.DevNum != v2.DevNum {
-			if v1.DevNum < v2.DevNum {
-				return -1
-			}
-			return 1
-		}
+	r1, k1, n1 := preKey(v1)
+	r2, k2, n2 := preKey(v2)
+	if c := cmp.Compare(r1, r2); c != 0 {
+		return c
 	}
-	// If both are pre, compare pre kind and number
-	if ph1 == 1 {
-		order := map[string]int{"a": 1, "b": 2, "rc": 3}
-		k1, k2 := order[v1.PreKind], order[v2.PreKind]
-		if k1 != k2 {
-			if k1 < k2 {
-				return -1
-			}
-			return 1
+	if r1 == finite {
+		if c := cmp.Compare(k1, k2); c != 0 {
+			return c
 		}
-		if v1.PreNum != v2.PreNum {
-			if v1.PreNum < v2.PreNum {
-				return -1
-			}
-			return 1
+		if c := n1.compare(n2); c != 0 {
+			return c
 		}
 	}
-	// If both are post, compare post numbers
-	if ph1 == 3 {
-		if v1.PostNum != v2.PostNum {
-			if v1.PostNum < v2.PostNum {
-				return -1
-			}
-			return 1
-		}
+	if c := compareSegment(v1.HasPost, v1.postNumeral(), v2.HasPost, v2.postNumeral(), negInf); c != 0 {
+		return c
+	}
+	if c := compareSegment(v1.HasDev, v1.devNumeral(), v2.HasDev, v2.devNumeral(), posInf); c != 0 {
+		return c
 	}
-	// If both are final, compare nothing (fall through to local)
+	return compareLocal(v1.Local, v2.Local)
+}
+
+// preKey returns the pre-release part of the comparison key as a sentinel
+// rank plus, for a finite rank, the kind order and number.
+func preKey(v Version) (rank, kind int, num numeral) {
+	switch {
+	case v.PreKind == "" && !v.HasPost && v.HasDev:
+		return negInf, 0, numeral{}
+	case v.PreKind == "":
+		return posInf, 0, numeral{}
+	}
+	return finite, preKindOrder[v.PreKind], v.preNumeral()
+}
 
-	// 4. Local version (only if all above are equal)
-	if len(v1.Local) == 0 && len(v2.Local) == 0 {
-		return 0
+// compareSegment compares an optional numeric segment, substituting the
+// given sentinel rank for an absent one.
+func compareSegment(has1 bool, n1 numeral, has2 bool, n2 numeral, absent int) int {
+	r1, r2 := finite, finite
+	if !has1 {
+		r1 = absent
 	}
-	if len(v1.Local) == 0 {
-		return -1
+	if !has2 {
+		r2 = absent
 	}
-	if len(v2.Local) == 0 {
-		return 1
+	if r1 != r2 || r1 != finite {
+		return cmp.Compare(r1, r2)
 	}
-	// Compare local segments
-	l1, l2 := v1.Local, v2.Local
-	minLen := len(l1)
-	if len(l2) < minLen {
-		minLen = len(l2)
+	return n1.compare(n2)
+}
+
+// releaseLen returns the length of the release segment once trailing zeros
+// are removed.
+func releaseLen(v Version) int {
+	n := len(v.Release)
+	for n > 0 && v.Release[n-1] == 0 {
+		n--
+	}
+	return n
+}
+
+// compareRelease compares release segments with trailing zeros stripped;
+// when one is a prefix of the other, the shorter sorts first.
+func compareRelease(v1, v2 Version) int {
+	n1, n2 := releaseLen(v1), releaseLen(v2)
+	for i := 0; i < n1 && i < n2; i++ {
+		if c := v1.releaseNumeral(i).compare(v2.releaseNumeral(i)); c != 0 {
+			return c
+		}
 	}
-	for i := 0; i < minLen; i++ {
+	return cmp.Compare(n1, n2)
+}
+
+// compareLocal compares local version labels. A missing label sorts first;
+// otherwise segments are compared pairwise with alphanumeric segments
+// sorting before numeric ones, and a shorter label sorting first on a tie.
+func compareLocal(l1, l2 []string) int {
+	if len(l1) == 0 || len(l2) == 0 {
+		return cmp.Compare(len(l1), len(l2))
+	}
+	for i := 0; i < len(l1) && i < len(l2); i++ {
 		s1, s2 := l1[i], l2[i]
-		isNum1 := isNumeric(s1)
-		isNum2 := isNumeric(s2)
+		isNum1 := isDigits(s1)
+		isNum2 := isDigits(s2)
 		if isNum1 && isNum2 {
-			n1, _ := strconv.Atoi(s1)
-			n2, _ := strconv.Atoi(s2)
-			if n1 != n2 {
-				if n1 < n2 {
-					return -1
-				}
-				return 1
+			if c := parseNumeral(s1).compare(parseNumeral(s2)); c != 0 {
+				return c
 			}
 		} else if isNum1 {
 			return 1 // numeric > lexicographic
@@ -500,23 +354,10 @@ func compareGoNative(v1, v2 Version) int {
 			return -1
 		} else {
 			// lexicographic, case-insensitive
-			cmp := strings.Compare(strings.ToLower(s1), strings.ToLower(s2))
-			if cmp != 0 {
-				return cmp
+			if c := strings.Compare(strings.ToLower(s1), strings.ToLower(s2)); c != 0 {
+				return c
 			}
 		}
 	}
-	// If all segments equal, longer local wins
-	if len(l1) < len(l2) {
-		return -1
-	}
-	if len(l1) > len(l2) {
-		return 1
-	}
-	return 0
-}
-
-func isNumeric(s string) bool {
-	_, err := strconv.Atoi(s)
-	return err == nil
+	return cmp.Compare(len(l1), len(l2))
 }
@@ -1,7 +1,33 @@
 #!/usr/bin/env python3
 import sys
 import json
-from packaging.version import Version, InvalidVersion
+
+# Exit status for a rejected version; the Go side turns it into a ParseError.
+INVALID_VERSION_EXIT = 3
+# Exit status when packaging cannot be imported, reported by the Go side as
+# ErrPackagingUnavailable.
+PACKAGING_MISSING_EXIT = 4
+
+try:
+    from packaging.specifiers import Specifier, SpecifierSet
+    from packaging.utils import canonicalize_version
+    from packaging.version import Version, InvalidVersion
+except ImportError as e:
+    print(f"pyver backend: {e} (interpreter {sys.executable})", file=sys.stderr)
+    sys.exit(PACKAGING_MISSING_EXIT)
+
+class Invalid(Exception):
+    """A version string packaging rejects."""
+
+    def __init__(self, version):
+        super().__init__(f"invalid version: {version!r}")
+        self.version = version
+
+def parse_version(s):
+    try:
+        return Version(s)
+    except InvalidVersion:
+        raise Invalid(s) from None
 
 def format_pre(pre):
     if pre is None:
@@ -25,46 +51,87 @@ def format_local(local):
         return ".".join(str(x) for x in local)
     return str(local)
 
+def describe(v):
+    return {
+        "normalized": str(v),
+        "public": v.public,
+        "base_version": v.base_version,
+        "is_prerelease": v.is_prerelease,
+        "is_postrelease": v.is_postrelease,
+        "is_devrelease": v.is_devrelease,
+        "major": v.major,
+        "minor": v.minor,
+        "micro": v.micro,
+        "canonical": canonicalize_version(v),
+        "epoch": v.epoch,
+        "release": v.release,
+        "pre": format_pre(v.pre),
+        "post": format_post(v.post),
+        "dev": format_dev(v.dev),
+        "local": format_local(v.local),
+    }
+
+def compare(a, b):
+    v1 = parse_version(a)
+    v2 = parse_version(b)
+    if v1 < v2:
+        return -1
+    if v1 > v2:
+        return 1
+    return 0
+
+def specifier_set(spec, prereleases):
+    """A SpecifierSet with prereleases given as "", "true" or "false"."""
+    return SpecifierSet(spec, prereleases={"": None, "true": True, "false": False}[prereleases])
+
+def handle(op, args):
+    if op == "parse":
+        return describe(parse_version(args[0]))
+    if op == "compare":
+        return compare(args[0], args[1])
+    if op == "sort":
+        return sorted(args, key=parse_version)
+    if op == "contains":
+        return Specifier(args[0]).contains(parse_version(args[1]))
+    if op == "satisfies":
+        return specifier_set(args[0], args[1]).contains(parse_version(args[2]))
+    if op == "filter":
+        return list(specifier_set(args[0], args[1]).filter(args[2:]))
+    raise ValueError(f"unknown command: {op}")
+
+def serve():
+    """Answer JSON-lines requests {"id", "op", "args"} until stdin closes.
+
+    Each response carries the request id and one of "result", "invalid"
+    (the rejected version string) or "error".
+    """
+    for line in iter(sys.stdin.readline, ""):
+        resp = {}
+        try:
+            req = json.loads(line)
+            resp["id"] = req["id"]
+            resp["result"] = handle(req["op"], req.get("args") or [])
+        except Invalid as e:
+            resp["invalid"] = e.version
+        except Exception as e:
+            resp["error"] = f"{type(e).__name__}: {e}"
+        print(json.dumps(resp), flush=True)
+
 def main():
+    if len(sys.argv) == 2 and sys.argv[1] == "serve":
+        serve()
+        return
     if len(sys.argv) < 3:
-        print("Usage: pyver_backend.py <command> <version(s)>", file=sys.stderr)
+        print("Usage: pyver_backend.py serve | <command> <version(s)>", file=sys.stderr)
         sys.exit(1)
-    cmd = sys.argv[1]
     try:
-        if cmd == "compare":
-            v1 = Version(sys.argv[2])
-            v2 = Version(sys.argv[3])
-            if v1 < v2:
-                print(-1)
-            elif v1 > v2:
-                print(1)
-            else:
-                print(0)
-        elif cmd == "parse":
-            v = Version(sys.argv[2])
-            print(json.dumps({
-                "normalized": str(v),
-                "public": v.public,
-                "base_version": v.base_version,
-                "is_prerelease": v.is_prerelease,
-                "is_postrelease": v.is_postrelease,
-                "is_devrelease": v.is_devrelease,
-                "epoch": v.epoch,
-                "release": v.release,
-                "pre": format_pre(v.pre),
-                "post": format_post(v.post),
-                "dev": format_dev(v.dev),
-                "local": format_local(v.local),
-            }))
-        else:
-            print(f"Unknown command: {cmd}", file=sys.stderr)
-            sys.exit(1)
-    except InvalidVersion as e:
-        print(f"Invalid version: {e}", file=sys.stderr)
-        sys.exit(1)
+        print(json.dumps(handle(sys.argv[1], sys.argv[2:])))
+    except Invalid as e:
+        print(json.dumps({"invalid": e.version}))
+        sys.exit(INVALID_VERSION_EXIT)
     except Exception as e:
         print(f"Error: {e}", file=sys.stderr)
         sys.exit(1)
 
 if __name__ == "__main__":
-    main()
\ No newline at end of file
+    main()
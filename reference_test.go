@@ -0,0 +1,201 @@
+package pyver
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// parseRegexp is the regular-expression parser that preceded the scanner,
+// kept as a reference for the differential test and benchmarks. The
+// expression and the spelling and separator rewrites are the original
+// ones, but it is not the original code: it stores numerals with the
+// Version setters, drops leading zeros from numeric local segments,
+// reports failures with invalidVersionError, and leaves out the debug
+// prints along with the release and local checks and the post_n1 branch
+// that the expression makes redundant.
+func parseRegexp(s string) (Version, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	s = strings.ToLower(s)
+	if strings.HasPrefix(s, "v") {
+		s = s[1:]
+	}
+
+	// Regex for PEP 440 (per Appendix B, with normalization flexibility)
+	var pep440Pattern = regexp.MustCompile(`^((?P<epoch>[0-9]+)!)?(?P<release>[0-9]+(?:\.[0-9]+)*)(?P<pre>([-_\.]?(preview|alpha|beta|rc|pre|c|a|b)[-_\.]?[0-9]*)?)?(?P<post>(-(?P<post_n1>[0-9]+))|(([-_\.]?(post|rev|r)[-_\.]?[0-9]*)))?(?P<dev>([-_\.]?dev[-_\.]?[0-9]*))?(\+(?P<local>[a-z0-9]+(?:[-_\.][a-z0-9]+)*))?$`)
+
+	m := pep440Pattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{Original: orig}, invalidVersionError(orig)
+	}
+
+	v := Version{Original: orig}
+	group := func(name string) string {
+		for i, n := range pep440Pattern.SubexpNames() {
+			if n == name {
+				return m[i]
+			}
+		}
+		return ""
+	}
+
+	// Epoch
+	if e := group("epoch"); e != "" {
+		v.setEpoch(parseNumeral(e))
+	}
+
+	// Release
+	rel := group("release")
+	for _, part := range strings.Split(rel, ".") {
+		v.appendRelease(parseNumeral(part))
+	}
+
+	// Pre-release
+	pre := group("pre")
+	if pre != "" {
+		// Normalize spelling and separator
+		pre = strings.ReplaceAll(pre, "_", "")
+		pre = strings.ReplaceAll(pre, "-", "")
+		pre = strings.ReplaceAll(pre, ".", "")
+		var kind string
+		for _, k := range []struct{ alt, norm string }{
+			{"preview", "rc"},
+			{"alpha", "a"}, {"a", "a"},
+			{"beta", "b"}, {"b", "b"},
+			{"pre", "rc"},
+			{"rc", "rc"}, {"c", "rc"},
+		} {
+			if strings.HasPrefix(pre, k.alt) {
+				kind = k.norm
+				pre = pre[len(k.alt):]
+				break
+			}
+		}
+		if kind != "" {
+			v.setPre(kind, parseNumeral(pre))
+		}
+	}
+
+	// Post-release
+	post := group("post")
+	if post != "" {
+		// Normalize spelling and separator
+		post = strings.ReplaceAll(post, "_", "")
+		post = strings.ReplaceAll(post, "-", "")
+		post = strings.ReplaceAll(post, ".", "")
+		if strings.HasPrefix(post, "post") {
+			post = post[4:]
+		} else if strings.HasPrefix(post, "rev") {
+			post = post[3:]
+		} else if strings.HasPrefix(post, "r") {
+			post = post[1:]
+		}
+		v.setPost(parseNumeral(post))
+	}
+
+	// Dev-release
+	dev := group("dev")
+	if dev != "" {
+		dev = strings.ReplaceAll(dev, "_", "")
+		dev = strings.ReplaceAll(dev, "-", "")
+		dev = strings.ReplaceAll(dev, ".", "")
+		if strings.HasPrefix(dev, "dev") {
+			dev = dev[3:]
+		}
+		v.setDev(parseNumeral(dev))
+	}
+
+	// Local version
+	local := group("local")
+	if local != "" {
+		// Normalize separators to '.'
+		for _, sep := range []string{"-", "_"} {
+			local = strings.ReplaceAll(local, sep, ".")
+		}
+		for _, part := range strings.Split(local, ".") {
+			// Numeric segments are compared by value, so drop leading zeros
+			if isDigits(part) {
+				part = parseNumeral(part).String()
+			}
+			v.Local = append(v.Local, part)
+		}
+	}
+
+	// Normalized string
+	v.Normalized = versionToString(v)
+	return v, nil
+}
+
+// referenceInputs gathers the inputs of the parse, compare and error tables.
+func referenceInputs() []string {
+	var inputs []string
+	inputs = append(inputs, validVersionCases...)
+	inputs = append(inputs, invalidVersionCases...)
+	inputs = append(inputs, orderedVersions...)
+	for _, tc := range roundtripCases {
+		inputs = append(inputs, tc.input)
+	}
+	for _, tc := range compareCases {
+		inputs = append(inputs, tc.v1, tc.v2)
+	}
+	return inputs
+}
+
+func TestParserMatchesRegexp(t *testing.T) {
+	for _, s := range referenceInputs() {
+		got, gotErr := parseGoNative(s)
+		want, wantErr := parseRegexp(s)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("parseGoNative(%q) = %+v, regexp parser gives %+v", s, got, want)
+		}
+		if !reflect.DeepEqual(gotErr, wantErr) {
+			t.Errorf("parseGoNative(%q) error = %v, regexp parser gives %v", s, gotErr, wantErr)
+		}
+	}
+}
+
+func TestParseAllocations(t *testing.T) {
+	cases := []struct {
+		input string
+		max   float64
+	}{
+		{"1.2.3", 1}, // the release slice
+		{"1!2.3.4a5.post6.dev7", 1},
+		{"1.0.0+abc.5", 2}, // plus the local slice
+		{"v1.0.0-RC1", 2},  // plus the normalized string
+	}
+	for _, tc := range cases {
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := parseGoNative(tc.input); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs > tc.max {
+			t.Errorf("parseGoNative(%q): %v allocations, want at most %v", tc.input, allocs, tc.max)
+		}
+	}
+}
+
+var benchInputs = []string{
+	"1.2.3", "2024.1", "1.0.0rc1", "1!2.3.4a5.post6.dev7+abc.def", "v1.0.0-RC1", "1.0.0.post1.dev2",
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, s := range benchInputs {
+			parseGoNative(s)
+		}
+	}
+}
+
+func BenchmarkParseRegexp(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, s := range benchInputs {
+			parseRegexp(s)
+		}
+	}
+}
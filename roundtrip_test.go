@@ -2,25 +2,41 @@ package pyver
 
 import "testing"
 
+// roundtripCases pair an input with its normalized form.
+var roundtripCases = []struct {
+	input    string
+	expected string // expected output after normalization, or same as input if no normalization
+}{
+	{"1.2.3", "1.2.3"},
+	{"1.0", "1.0"}, // normalization: packaging normalizes '1.0' to '1.0'
+	{"1.0.0", "1.0.0"},
+	{"1.0.0a1", "1.0.0a1"},
+	{"1.0.0.post1", "1.0.0.post1"},
+	{"1.0.0.dev2", "1.0.0.dev2"},
+	{"1!1.0.0", "1!1.0.0"},
+	{"1.0.0+abc", "1.0.0+abc"},
+	{"1.0.0-rc1", "1.0.0rc1"}, // normalization
+	{"1.02.3", "1.2.3"},       // leading zero normalization
+	{"1.0.0.post1.dev2", "1.0.0.post1.dev2"},
+	{"2!3.4.5a1.post2.dev3+meta", "2!3.4.5a1.post2.dev3+meta"},
+	// Zero-numbered segments are kept, matching packaging
+	{"1.0.post0", "1.0.post0"},
+	{"1.0.dev0", "1.0.dev0"},
+	{"1.0.post0.dev0", "1.0.post0.dev0"},
+	{"1.0-0", "1.0.post0"},
+	{"1.0.post", "1.0.post0"},
+	{"1.0dev", "1.0.dev0"},
+	{"1.0a", "1.0a0"},
+	// Numerals of any size are kept exactly
+	{"1.20240101123045123456789", "1.20240101123045123456789"},
+	{"99999999999999999999999!1.0", "99999999999999999999999!1.0"},
+	{"1.0rc00000000000000000000000000001", "1.0rc1"},
+	{"1.0.post123456789012345678901234567890.dev98765432109876543210", "1.0.post123456789012345678901234567890.dev98765432109876543210"},
+	{"1.0+007.abc", "1.0+7.abc"},
+}
+
 func TestRoundtripParseString(t *testing.T) {
-	cases := []struct {
-		input    string
-		expected string // expected output after normalization, or same as input if no normalization
-	}{
-		{"1.2.3", "1.2.3"},
-		{"1.0", "1.0"}, // normalization: packaging normalizes '1.0' to '1.0'
-		{"1.0.0", "1.0.0"},
-		{"1.0.0a1", "1.0.0a1"},
-		{"1.0.0.post1", "1.0.0.post1"},
-		{"1.0.0.dev2", "1.0.0.dev2"},
-		{"1!1.0.0", "1!1.0.0"},
-		{"1.0.0+abc", "1.0.0+abc"},
-		{"1.0.0-rc1", "1.0.0rc1"}, // normalization
-		{"1.02.3", "1.2.3"},       // leading zero normalization
-		{"1.0.0.post1.dev2", "1.0.0.post1.dev2"},
-		{"2!3.4.5a1.post2.dev3+meta", "2!3.4.5a1.post2.dev3+meta"},
-	}
-	for _, tc := range cases {
+	for _, tc := range roundtripCases {
 		t.Run(tc.input, func(t *testing.T) {
 			v, err := Parse(tc.input)
 			if err != nil {
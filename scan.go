@@ -0,0 +1,346 @@
+package pyver
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scanner walks a version string through the PEP 440 grammar (Appendix B,
+// including the alternate spellings and separators packaging accepts) and
+// reports the first place where the input leaves it. Along the way it
+// collects the segment values and records which normalization rules the
+// input relies on. It is the Go-native parser and allocates only for the
+// slices of the resulting Version and, if needed, its normalized string.
+type scanner struct {
+	in  string  // the original input
+	pos int     // current byte offset in in
+	end int     // offset just past the last non-space byte
+	seg Segment // segment most recently entered
+	v   Version // segment values read so far
+
+	norms   normSet // normalization rules the input needs
+	normAt  int     // offset of the first of them
+	normSeg Segment // segment of the first of them
+}
+
+// suffixKeyword is a pre-, post- or dev-release spelling together with the
+// normalized pre-release kind it stands for.
+type suffixKeyword struct {
+	word string
+	seg  Segment
+	kind string
+}
+
+// suffixKeywords lists the pre-, post- and dev-release spellings. Longer
+// spellings come first so that "rev" is not read as "r" followed by "ev".
+var suffixKeywords = []suffixKeyword{
+	{"preview", SegmentPre, "rc"},
+	{"alpha", SegmentPre, "a"},
+	{"beta", SegmentPre, "b"},
+	{"post", SegmentPost, ""},
+	{"pre", SegmentPre, "rc"},
+	{"rev", SegmentPost, ""},
+	{"dev", SegmentDev, ""},
+	{"rc", SegmentPre, "rc"},
+	{"a", SegmentPre, "a"},
+	{"b", SegmentPre, "b"},
+	{"c", SegmentPre, "rc"},
+	{"r", SegmentPost, ""},
+}
+
+// segmentRank orders the suffix segments as they must appear.
+var segmentRank = map[Segment]int{
+	SegmentRelease: 0,
+	SegmentPre:     1,
+	SegmentPost:    2,
+	SegmentDev:     3,
+}
+
+// diagnose scans s and returns a *ParseError for the first violation of
+// the grammar, or nil if s is a valid version.
+func diagnose(s string) *ParseError {
+	sc := scanner{in: s, seg: SegmentRelease}
+	return sc.scan()
+}
+
+func (sc *scanner) fail(r Reason) *ParseError {
+	return &ParseError{Input: sc.in, Offset: sc.pos, Segment: sc.seg, Reason: r, Err: ErrInvalidVersion}
+}
+
+func (sc *scanner) peek() byte {
+	if sc.pos < sc.end {
+		return sc.in[sc.pos]
+	}
+	return 0
+}
+
+// digits consumes a run of ASCII digits and reports whether there was one.
+func (sc *scanner) digits() bool {
+	start := sc.pos
+	for sc.pos < sc.end && isDigit(sc.in[sc.pos]) {
+		sc.pos++
+	}
+	return sc.pos > start
+}
+
+// number consumes a required numeral.
+func (sc *scanner) number() *ParseError {
+	if sc.digits() {
+		return nil
+	}
+	if sc.pos == sc.end {
+		return sc.fail(ReasonUnexpectedEnd)
+	}
+	return sc.fail(ReasonMissingNumber)
+}
+
+// keyword consumes the longest suffix spelling at the current position.
+func (sc *scanner) keyword() (suffixKeyword, bool) {
+	rest := sc.in[sc.pos:sc.end]
+	for _, k := range suffixKeywords {
+		if hasPrefixFold(rest, k.word) {
+			sc.pos += len(k.word)
+			return k, true
+		}
+	}
+	return suffixKeyword{}, false
+}
+
+// note records that the input needs rule r at offset at.
+func (sc *scanner) note(r Normalization, at int) {
+	if sc.norms == 0 || at < sc.normAt {
+		sc.normAt, sc.normSeg = at, sc.seg
+	}
+	sc.norms |= 1 << r
+}
+
+// noteZeros records leading zeros in the numeral starting at start.
+func (sc *scanner) noteZeros(start int) {
+	if sc.pos-start > 1 && sc.in[start] == '0' {
+		sc.note(NormLeadingZeros, start)
+	}
+}
+
+// hasPrefixFold reports whether s begins with the lower-case ASCII word,
+// ignoring ASCII case.
+func hasPrefixFold(s, word string) bool {
+	if len(s) < len(word) {
+		return false
+	}
+	for i := 0; i < len(word); i++ {
+		if s[i]|0x20 != word[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (sc *scanner) scan() *ParseError {
+	sc.end = len(strings.TrimRightFunc(sc.in, isSpace))
+	sc.pos = len(sc.in) - len(strings.TrimLeftFunc(sc.in, isSpace))
+	if sc.pos >= sc.end {
+		sc.pos = 0
+		return sc.fail(ReasonEmpty)
+	}
+	if sc.pos > 0 {
+		sc.note(NormWhitespace, 0)
+	}
+	if err := sc.scanVersion(); err != nil {
+		return err
+	}
+	if sc.end < len(sc.in) {
+		sc.note(NormWhitespace, sc.end)
+	}
+	return nil
+}
+
+func (sc *scanner) scanVersion() *ParseError {
+	if c := sc.peek(); c == 'v' || c == 'V' {
+		sc.note(NormVPrefix, sc.pos)
+		sc.pos++
+	}
+
+	// Epoch and release
+	start := sc.pos
+	if err := sc.number(); err != nil {
+		return err
+	}
+	hasEpoch := sc.peek() == '!'
+	if hasEpoch {
+		sc.seg = SegmentEpoch
+		sc.v.setEpoch(parseNumeral(sc.in[start:sc.pos]))
+		if strings.Trim(sc.in[start:sc.pos], "0") == "" {
+			sc.note(NormZeroEpoch, start)
+		} else {
+			sc.noteZeros(start)
+		}
+		sc.seg = SegmentRelease
+		sc.pos++
+		start = sc.pos
+		if err := sc.number(); err != nil {
+			return err
+		}
+	}
+	relStart := start
+	sc.noteZeros(start)
+	for sc.peek() == '.' && sc.pos+1 < sc.end && isDigit(sc.in[sc.pos+1]) {
+		sc.pos++
+		start = sc.pos
+		sc.digits()
+		sc.noteZeros(start)
+	}
+	sc.release(sc.in[relStart:sc.pos])
+	if sc.peek() == '!' {
+		sc.seg = SegmentEpoch
+		if hasEpoch {
+			return sc.fail(ReasonDuplicateSegment)
+		}
+		return sc.fail(ReasonUnexpectedCharacter)
+	}
+
+	// Pre-, post- and dev-release suffixes
+	for sc.pos < sc.end && sc.peek() != '+' {
+		start := sc.pos
+		sep := sc.peek()
+		if isSeparator(sep) {
+			sc.pos++
+		}
+		kwStart := sc.pos
+		kw, ok := sc.keyword()
+		implicit := !ok && sep == '-' && isDigit(sc.peek())
+		if implicit {
+			kw, ok = suffixKeyword{seg: SegmentPost}, true // implicit post release, e.g. "1.0-1"
+		}
+		word, seg := kw.word, kw.seg
+		if !ok {
+			if sc.pos == sc.end {
+				return sc.fail(ReasonUnexpectedEnd)
+			}
+			if sep == '.' && sc.seg == SegmentRelease && !isLetter(sc.peek()) {
+				return sc.fail(ReasonMissingNumber)
+			}
+			return sc.fail(ReasonUnexpectedCharacter)
+		}
+		if cur := segmentRank[sc.seg]; segmentRank[seg] <= cur {
+			reason := ReasonDuplicateSegment
+			if segmentRank[seg] < cur {
+				reason = ReasonMisorderedSegment
+			}
+			sc.pos, sc.seg = start, seg
+			return sc.fail(reason)
+		}
+		sc.seg = seg
+		rules := suffixRules[seg]
+		// Canonically a pre-release follows the release directly, while
+		// post- and dev-releases are introduced by a single '.'
+		switch {
+		case implicit:
+			sc.note(NormImplicitPost, start)
+		case seg == SegmentPre:
+			if isSeparator(sep) {
+				sc.note(rules.separator, start)
+			}
+		case sep != '.':
+			sc.note(rules.separator, start)
+		}
+		if sc.in[kwStart:sc.pos] != word {
+			sc.note(NormCase, kwStart)
+		}
+		if !implicit && rules.canonical != nil && !rules.canonical[word] {
+			sc.note(rules.spelling, kwStart)
+		}
+		// The number may follow a separator; a separator may also trail a
+		// spelling without a number, as in "1.0a-" or "1.0.post.dev1".
+		if isSeparator(sc.peek()) {
+			sc.note(rules.separator, sc.pos)
+			sc.pos++
+		}
+		start = sc.pos
+		if !sc.digits() {
+			sc.note(rules.number, start)
+		}
+		sc.noteZeros(start)
+		n := parseNumeral(sc.in[start:sc.pos])
+		switch seg {
+		case SegmentPre:
+			sc.v.setPre(kw.kind, n)
+		case SegmentPost:
+			sc.v.setPost(n)
+		case SegmentDev:
+			sc.v.setDev(n)
+		}
+	}
+
+	// Local version label
+	if sc.pos == sc.end {
+		return nil
+	}
+	sc.pos++ // '+'
+	sc.seg = SegmentLocal
+	n := 1
+	for i := sc.pos; i < sc.end; i++ {
+		if isSeparator(sc.in[i]) {
+			n++
+		}
+	}
+	sc.v.Local = make([]string, 0, n)
+	for {
+		if sc.pos == sc.end {
+			return sc.fail(ReasonUnexpectedEnd)
+		}
+		if !isAlnum(sc.in[sc.pos]) {
+			return sc.fail(ReasonUnexpectedCharacter)
+		}
+		start := sc.pos
+		for sc.pos < sc.end && isAlnum(sc.in[sc.pos]) {
+			if sc.in[sc.pos] < 'a' && isLetter(sc.in[sc.pos]) {
+				sc.note(NormCase, sc.pos)
+			}
+			sc.pos++
+		}
+		part := sc.in[start:sc.pos]
+		if isDigits(part) {
+			sc.noteZeros(start)
+			// Numeric segments are compared by value, so drop leading zeros
+			if part = strings.TrimLeft(part, "0"); part == "" {
+				part = "0"
+			}
+		}
+		sc.v.Local = append(sc.v.Local, strings.ToLower(part))
+		if sc.pos == sc.end {
+			return nil
+		}
+		if !isSeparator(sc.in[sc.pos]) {
+			return sc.fail(ReasonUnexpectedCharacter)
+		}
+		if sc.in[sc.pos] != '.' {
+			sc.note(NormLocalSeparator, sc.pos)
+		}
+		sc.pos++
+	}
+}
+
+// release stores the dot-separated numerals of rel as the release segment.
+func (sc *scanner) release(rel string) {
+	sc.v.Release = make([]int, 0, strings.Count(rel, ".")+1)
+	for {
+		part, rest, more := strings.Cut(rel, ".")
+		sc.v.appendRelease(parseNumeral(part))
+		if !more {
+			return
+		}
+		rel = rest
+	}
+}
+
+// isSpace matches Python's \s, which also counts the information separators
+// U+001C to U+001F as whitespace.
+func isSpace(r rune) bool { return unicode.IsSpace(r) || '\x1c' <= r && r <= '\x1f' }
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func isLetter(c byte) bool { return 'a' <= c|0x20 && c|0x20 <= 'z' }
+
+func isAlnum(c byte) bool { return isDigit(c) || isLetter(c) }
+
+func isSeparator(c byte) bool { return c == '.' || c == '-' || c == '_' }
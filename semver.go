@@ -0,0 +1,253 @@
+package pyver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidSemVer is matched by the errors FromSemVer returns for a string
+// that is not a SemVer 2.0 version.
+var ErrInvalidSemVer = errors.New("invalid SemVer version")
+
+// ErrSemVerMapping is matched by the errors FromSemVer and Version.SemVer
+// return when a version has no equivalent in the other scheme, or, with
+// StrictSemVer, no exact one.
+var ErrSemVerMapping = errors.New("no SemVer mapping")
+
+// SemVerOption adjusts the conversions between SemVer and PEP 440.
+type SemVerOption func(*semverOptions)
+
+type semverOptions struct {
+	strict bool
+}
+
+// StrictSemVer makes FromSemVer and Version.SemVer fail with
+// ErrSemVerMapping unless the mapping is exact: the result converts back to
+// the input, and the conversion preserves the order of every version it
+// accepts, apart from build metadata, which SemVer ignores in ordering.
+func StrictSemVer() SemVerOption {
+	return func(o *semverOptions) { o.strict = true }
+}
+
+func semverOpts(opts []SemVerOption) semverOptions {
+	var o semverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// semverPhases maps the SemVer pre-release names FromSemVer accepts to PEP
+// 440 kinds, and PEP 440 kinds to the names Version.SemVer writes.
+var (
+	semverPhases = map[string]string{
+		"alpha": "a", "a": "a", "beta": "b", "b": "b",
+		"rc": "rc", "c": "rc", "pre": "rc", "preview": "rc",
+	}
+	semverNames = map[string]string{"a": "alpha", "b": "beta", "rc": "rc"}
+)
+
+// FromSemVer converts a SemVer 2.0 version, optionally prefixed with "v",
+// to PEP 440: "1.2.3-rc.1+abc" -> "1.2.3rc1+abc". The pre-release must be
+// a phase (alpha, beta or rc, or a, b, c, pre or preview), then
+// optionally "post" and "dev", each with an optional number, separated by
+// dots or attached: "alpha.1.post.2", "rc1", "dev.3". Build metadata
+// becomes the local label.
+//
+// The mapping is lossy for:
+//
+//   - spellings other than Version.SemVer writes, such as "v1.2.3",
+//     "1.2.3-RC1" or "1.2.3-alpha", which is "1.2.3a0" like "1.2.3-alpha.0";
+//   - dev-releases, which SemVer sorts after alpha pre-releases of the same
+//     version while PEP 440 sorts them before;
+//   - build metadata, which SemVer ignores in ordering but PEP 440 does not,
+//     and whose hyphens and upper-case letters PEP 440 normalizes.
+//
+// Any other pre-release, such as "1.0.0-x.7", has no PEP 440 equivalent.
+// With StrictSemVer every lossy mapping is an error too.
+func FromSemVer(s string, opts ...SemVerOption) (Version, error) {
+	fail := func(err error, reason string) (Version, error) {
+		return Version{}, fmt.Errorf("%w %q: %s", err, s, reason)
+	}
+	core, build, hasBuild := strings.Cut(strings.TrimPrefix(s, "v"), "+")
+	core, pre, hasPre := strings.Cut(core, "-")
+	nums := strings.Split(core, ".")
+	if len(nums) != 3 {
+		return fail(ErrInvalidSemVer, "expected major.minor.patch")
+	}
+	for _, n := range nums {
+		if !semverNumeric(n) {
+			return fail(ErrInvalidSemVer, fmt.Sprintf("invalid version number %q", n))
+		}
+	}
+	if hasPre && !semverIdentifiers(pre, true) || hasBuild && !semverIdentifiers(build, false) {
+		return fail(ErrInvalidSemVer, "invalid pre-release or build metadata")
+	}
+
+	var b strings.Builder
+	b.WriteString(core)
+	if hasPre {
+		suffix, ok := semverSuffix(strings.ToLower(pre))
+		if !ok {
+			return fail(ErrSemVerMapping, fmt.Sprintf("pre-release %q has no PEP 440 equivalent", pre))
+		}
+		b.WriteString(suffix)
+	}
+	if hasBuild {
+		b.WriteString("+" + build)
+	}
+	v, err := parseGoNative(b.String())
+	if err != nil {
+		return fail(ErrSemVerMapping, "build metadata is not a valid local label")
+	}
+	v.Original = v.Normalized
+	if semverOpts(opts).strict {
+		if back, err := v.SemVer(opts...); err != nil || back != s {
+			return fail(ErrSemVerMapping, "the mapping to "+v.String()+" is not exact")
+		}
+	}
+	return v, nil
+}
+
+// semverSuffix translates dot-separated lower-case pre-release identifiers
+// to PEP 440 pre-, post- and dev-release segments.
+func semverSuffix(pre string) (string, bool) {
+	var ids []string
+	for id := range strings.SplitSeq(pre, ".") {
+		// Split an attached number: "rc1" -> "rc", "1".
+		i := len(id)
+		for i > 0 && isDigit(id[i-1]) {
+			i--
+		}
+		if i > 0 && i < len(id) {
+			ids = append(ids, id[:i], id[i:])
+		} else {
+			ids = append(ids, id)
+		}
+	}
+	var b strings.Builder
+	// Each segment is a name and an optional number; post needs a phase,
+	// as SemVer sorts every pre-release before the release.
+	next := func(name string) (n string, ok bool) {
+		if len(ids) == 0 || ids[0] != name {
+			return "", false
+		}
+		ids = ids[1:]
+		if len(ids) > 0 && isDigits(ids[0]) {
+			n, ids = ids[0], ids[1:]
+		}
+		return n, true
+	}
+	var phase string
+	if len(ids) > 0 {
+		phase = semverPhases[ids[0]]
+	}
+	if phase != "" {
+		n, _ := next(ids[0])
+		b.WriteString(phase + n)
+		if n, ok := next("post"); ok {
+			b.WriteString(".post" + n)
+		}
+	}
+	if n, ok := next("dev"); ok {
+		b.WriteString(".dev" + n)
+	}
+	return b.String(), len(ids) == 0 && b.Len() > 0
+}
+
+// semverNumeric reports whether s is a SemVer numeric identifier: digits
+// without leading zeros.
+func semverNumeric(s string) bool {
+	return isDigits(s) && (s == "0" || s[0] != '0')
+}
+
+// semverIdentifiers reports whether s is a non-empty list of dot-separated
+// alphanumeric identifiers, numeric ones without leading zeros if numeric
+// is set.
+func semverIdentifiers(s string, numeric bool) bool {
+	for id := range strings.SplitSeq(s, ".") {
+		if id == "" || strings.ContainsFunc(id, func(r rune) bool {
+			return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-')
+		}) {
+			return false
+		}
+		if numeric && isDigits(id) && !semverNumeric(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// SemVer converts v to a SemVer 2.0 version, padding the release to three
+// components and writing pre-releases as alpha, beta and rc:
+// "1.2.3rc1+abc" -> "1.2.3-rc.1+abc", "1.2a1.post1" -> "1.2.0-alpha.1.post.1".
+//
+// A version with an epoch or more than three release components (not
+// counting trailing zeros) has no SemVer equivalent. The mapping is lossy
+// for:
+//
+//   - dev-releases, written as "-dev.N" or "-alpha.1.dev.N", which SemVer
+//     sorts after the alpha pre-releases of the same version;
+//   - post-releases of a final release, written as build metadata
+//     "+post.N", which SemVer sorts equal to the release;
+//   - local labels, written as build metadata, which SemVer ignores in
+//     ordering.
+//
+// With StrictSemVer dev-releases and post-releases of final releases are
+// errors; the local label is kept.
+func (v Version) SemVer(opts ...SemVerOption) (string, error) {
+	fail := func(reason string) (string, error) {
+		return "", fmt.Errorf("%w %q: %s", ErrSemVerMapping, v.String(), reason)
+	}
+	strict := semverOpts(opts).strict
+	switch {
+	case !v.epochNumeral().isZero():
+		return fail("SemVer has no epoch")
+	case releaseLen(v) > 3:
+		return fail("SemVer has only three release components")
+	case strict && v.HasDev:
+		return fail("SemVer sorts dev-releases after alpha pre-releases")
+	case strict && v.HasPost && v.PreKind == "":
+		return fail("SemVer has no post-releases")
+	}
+	var b strings.Builder
+	for i := range 3 {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		if i < len(v.Release) {
+			b.WriteString(v.releaseNumeral(i).String())
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	var pre, build []string
+	if v.PreKind != "" {
+		pre = append(pre, semverNames[v.PreKind], v.preNumeral().String())
+	}
+	if v.HasPost {
+		post := []string{"post", v.postNumeral().String()}
+		if v.PreKind != "" {
+			pre = append(pre, post...)
+		} else {
+			build = append(build, post...)
+		}
+	}
+	if v.HasDev {
+		dev := []string{"dev", v.devNumeral().String()}
+		if v.HasPost && v.PreKind == "" {
+			build = append(build, dev...)
+		} else {
+			pre = append(pre, dev...)
+		}
+	}
+	build = append(build, v.Local...)
+	if len(pre) > 0 {
+		b.WriteString("-" + strings.Join(pre, "."))
+	}
+	if len(build) > 0 {
+		b.WriteString("+" + strings.Join(build, "."))
+	}
+	return b.String(), nil
+}
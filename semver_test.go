@@ -0,0 +1,186 @@
+package pyver
+
+import (
+	"cmp"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFromSemVer(t *testing.T) {
+	tests := []struct {
+		input, want string
+		strict      bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3-rc.1+abc", "1.2.3rc1+abc", true},
+		{"1.2.3-alpha.0", "1.2.3a0", true},
+		{"1.2.3-beta.11", "1.2.3b11", true},
+		{"1.2.3-alpha.1.post.2", "1.2.3a1.post2", true},
+		{"0.0.0", "0.0.0", true},
+		{"1.2.3+build.5", "1.2.3+build.5", true},
+		{"v1.2.3", "1.2.3", false},
+		{"1.2.3-alpha", "1.2.3a0", false},
+		{"1.2.3-RC1", "1.2.3rc1", false},
+		{"1.2.3-c.1", "1.2.3rc1", false},
+		{"1.2.3-preview.2", "1.2.3rc2", false},
+		{"1.2.3-dev.4", "1.2.3.dev4", false},
+		{"1.2.3-alpha.1.dev.4", "1.2.3a1.dev4", false},
+		{"1.2.3+Build-5", "1.2.3+build.5", false},
+	}
+	for _, tc := range tests {
+		v, err := FromSemVer(tc.input)
+		if err != nil || v.String() != tc.want {
+			t.Errorf("FromSemVer(%q) = %q, %v, want %q", tc.input, v, err, tc.want)
+		}
+		if _, err := FromSemVer(tc.input, StrictSemVer()); (err == nil) != tc.strict {
+			t.Errorf("FromSemVer(%q, StrictSemVer()) = %v, want strict %v", tc.input, err, tc.strict)
+		}
+	}
+}
+
+func TestFromSemVerInvalid(t *testing.T) {
+	for _, s := range []string{"", "1.2", "1.2.3.4", "01.2.3", "1.2.3-", "1.2.3-alpha..1", "1.2.3-alpha.01", "1.2.3+", "1.2.3-a_b", "a.b.c"} {
+		if v, err := FromSemVer(s); !errors.Is(err, ErrInvalidSemVer) {
+			t.Errorf("FromSemVer(%q) = %q, %v, want ErrInvalidSemVer", s, v, err)
+		}
+	}
+	for _, s := range []string{"1.0.0-x.7", "1.0.0-1", "1.0.0-post.1", "1.0.0-alpha.beta", "1.0.0-rc.1.x", "1.0.0+a--b"} {
+		if v, err := FromSemVer(s); !errors.Is(err, ErrSemVerMapping) {
+			t.Errorf("FromSemVer(%q) = %q, %v, want ErrSemVerMapping", s, v, err)
+		}
+	}
+}
+
+func TestVersionSemVer(t *testing.T) {
+	tests := []struct {
+		input, want string
+		strict      bool
+	}{
+		{"1.2.3rc1+abc", "1.2.3-rc.1+abc", true},
+		{"1.2", "1.2.0", true},
+		{"1", "1.0.0", true},
+		{"1.2.3.0", "1.2.3", true},
+		{"1.2a1.post1", "1.2.0-alpha.1.post.1", true},
+		{"2.0b2", "2.0.0-beta.2", true},
+		{"1.0.dev1", "1.0.0-dev.1", false},
+		{"1.0rc1.dev1", "1.0.0-rc.1.dev.1", false},
+		{"1.0.post2", "1.0.0+post.2", false},
+		{"1.0.post2.dev1+abc", "1.0.0+post.2.dev.1.abc", false},
+	}
+	for _, tc := range tests {
+		v := MustParse(tc.input)
+		got, err := v.SemVer()
+		if err != nil || got != tc.want {
+			t.Errorf("%q.SemVer() = %q, %v, want %q", tc.input, got, err, tc.want)
+		}
+		if _, err := v.SemVer(StrictSemVer()); (err == nil) != tc.strict {
+			t.Errorf("%q.SemVer(StrictSemVer()) = %v, want strict %v", tc.input, err, tc.strict)
+		}
+	}
+	for _, s := range []string{"1!1.0", "1.2.3.4", "1.0.0.0.1"} {
+		if got, err := MustParse(s).SemVer(); !errors.Is(err, ErrSemVerMapping) {
+			t.Errorf("%q.SemVer() = %q, %v, want ErrSemVerMapping", s, got, err)
+		}
+	}
+}
+
+// compareSemVer orders SemVer versions by precedence, as in section 11 of
+// the SemVer 2.0 specification.
+func compareSemVer(a, b string) int {
+	ids := func(s string) (core, pre []string) {
+		s, _, _ = strings.Cut(s, "+")
+		c, p, ok := strings.Cut(s, "-")
+		if ok {
+			pre = strings.Split(p, ".")
+		}
+		return strings.Split(c, "."), pre
+	}
+	num := func(x, y string) int {
+		return cmp.Or(cmp.Compare(len(x), len(y)), strings.Compare(x, y))
+	}
+	ca, pa := ids(a)
+	cb, pb := ids(b)
+	for i := range 3 {
+		if c := num(ca[i], cb[i]); c != 0 {
+			return c
+		}
+	}
+	if pa == nil || pb == nil {
+		return cmp.Compare(len(pb), len(pa))
+	}
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		x, y := pa[i], pb[i]
+		var c int
+		switch {
+		case isDigits(x) && isDigits(y):
+			c = num(x, y)
+		case isDigits(x):
+			c = -1
+		case isDigits(y):
+			c = 1
+		default:
+			c = strings.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(pa), len(pb))
+}
+
+// semverVersions returns PEP 440 versions that StrictSemVer accepts.
+func semverVersions() []Version {
+	var vs []Version
+	for _, release := range []string{"0.0.1", "0.1", "1", "1.0.1", "1.2", "1.10", "2.0.0", "10"} {
+		vs = append(vs, MustParse(release))
+		for _, kind := range []string{"a", "b", "rc"} {
+			for _, n := range []string{"0", "1", "2", "10"} {
+				vs = append(vs, MustParse(release+kind+n), MustParse(release+kind+n+".post1"))
+			}
+		}
+	}
+	return vs
+}
+
+// TestSemVerOrder checks that exact mappings preserve the order of Compare
+// in both directions.
+func TestSemVerOrder(t *testing.T) {
+	vs := semverVersions()
+	svs := make([]string, len(vs))
+	for i, v := range vs {
+		s, err := v.SemVer(StrictSemVer())
+		if err != nil {
+			t.Fatalf("%q.SemVer(StrictSemVer()): %v", v, err)
+		}
+		back, err := FromSemVer(s, StrictSemVer())
+		if err != nil || back.Compare(v) != 0 {
+			t.Fatalf("FromSemVer(%q, StrictSemVer()) = %q, %v, want %q", s, back, err, v)
+		}
+		svs[i] = s
+	}
+	for i := range vs {
+		for j := range vs {
+			if got, want := compareSemVer(svs[i], svs[j]), vs[i].Compare(vs[j]); got != want {
+				t.Errorf("SemVer %q vs %q = %d, Compare(%q, %q) = %d", svs[i], svs[j], got, vs[i], vs[j], want)
+			}
+		}
+	}
+
+	// The precedence example of the SemVer specification, without
+	// "1.0.0-alpha.beta", which has no PEP 440 equivalent.
+	spec := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0"}
+	for i := 1; i < len(spec); i++ {
+		a, b := must(FromSemVer(spec[i-1])), must(FromSemVer(spec[i]))
+		if compareSemVer(spec[i-1], spec[i]) >= 0 || a.Compare(b) >= 0 {
+			t.Errorf("%q (%q) does not sort before %q (%q)", spec[i-1], a, spec[i], b)
+		}
+	}
+}
+
+func must(v Version, err error) Version {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
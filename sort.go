@@ -0,0 +1,68 @@
+package pyver
+
+import (
+	"context"
+	"slices"
+)
+
+// Sort sorts vs in increasing PEP 440 order. Versions that compare equal,
+// such as "1.0" and "1.0.0", keep their relative order.
+func Sort(vs []Version) {
+	slices.SortStableFunc(vs, Compare)
+}
+
+// SortStrings sorts version strings, such as release tags, in increasing
+// PEP 440 order without rewriting them. If any string is not a valid
+// version, ss is left unchanged and the parse error is returned.
+//
+// A backend that can sort strings itself, such as *Worker, does so in a
+// single operation.
+func SortStrings(ss []string) error {
+	return SortStringsContext(context.Background(), ss)
+}
+
+// SortStringsContext is like SortStrings but bounds a backend that sorts
+// strings itself by ctx: if ctx is done before it answers, ss is left
+// unchanged and a *CanceledError is returned.
+func SortStringsContext(ctx context.Context, ss []string) error {
+	if s, ok := DefaultBackend().(stringSorter); ok {
+		return s.SortStringsContext(ctx, ss)
+	}
+	vs := make([]Version, len(ss))
+	for i, s := range ss {
+		v, err := ParseContext(ctx, s)
+		if err != nil {
+			return err
+		}
+		vs[i] = v
+	}
+	Sort(vs)
+	for i, v := range vs {
+		ss[i] = v.Original
+	}
+	return nil
+}
+
+// Max returns the greatest of vs, or the zero Version if vs is empty. Of
+// several equal maxima the first is returned.
+func Max(vs ...Version) Version {
+	var m Version
+	for i, v := range vs {
+		if i == 0 || Compare(v, m) > 0 {
+			m = v
+		}
+	}
+	return m
+}
+
+// Min returns the least of vs, or the zero Version if vs is empty. Of
+// several equal minima the first is returned.
+func Min(vs ...Version) Version {
+	var m Version
+	for i, v := range vs {
+		if i == 0 || Compare(v, m) < 0 {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,100 @@
+package pyver
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestVersionMethods(t *testing.T) {
+	tests := []struct {
+		v1, v2      string
+		less, equal bool
+	}{
+		{"1.0", "1.0.0", false, true},
+		{"1.0", "2.0", true, false},
+		{"1.0rc1", "1.0", true, false},
+		{"1.0+abc", "1.0", false, false},
+		{"1.0-1", "1.0.post1", false, true},
+	}
+	for _, tc := range tests {
+		v1, v2 := MustParse(tc.v1), MustParse(tc.v2)
+		if got := v1.Less(v2); got != tc.less {
+			t.Errorf("%s.Less(%s) = %v, want %v", tc.v1, tc.v2, got, tc.less)
+		}
+		if got := v1.Equal(v2); got != tc.equal {
+			t.Errorf("%s.Equal(%s) = %v, want %v", tc.v1, tc.v2, got, tc.equal)
+		}
+		if got, want := v1.Compare(v2), Compare(v1, v2); got != want {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tc.v1, tc.v2, got, want)
+		}
+	}
+}
+
+func TestTryCompare(t *testing.T) {
+	c, err := TryCompare(MustParse("1.0a1"), MustParse("1.0"))
+	if err != nil || c != -1 {
+		t.Errorf("TryCompare = %d, %v, want -1, nil", c, err)
+	}
+}
+
+func TestSort(t *testing.T) {
+	vs := make([]Version, len(orderedVersions))
+	for i, s := range orderedVersions {
+		vs[len(vs)-1-i] = MustParse(s)
+	}
+	Sort(vs)
+	for i, v := range vs {
+		if v.Original != orderedVersions[i] {
+			t.Errorf("Sort: position %d holds %q, want %q", i, v.Original, orderedVersions[i])
+		}
+	}
+
+	// Equal versions keep their order.
+	vs = []Version{MustParse("1.0.0"), MustParse("0.9"), MustParse("1.0")}
+	Sort(vs)
+	if got := []string{vs[0].Original, vs[1].Original, vs[2].Original}; !slices.Equal(got, []string{"0.9", "1.0.0", "1.0"}) {
+		t.Errorf("Sort with ties = %q", got)
+	}
+
+	// Compare works with the slices package directly.
+	vs = []Version{MustParse("2.0"), MustParse("1.0")}
+	slices.SortFunc(vs, Compare)
+	if vs[0].Original != "1.0" {
+		t.Errorf("slices.SortFunc(Compare) = %v", vs)
+	}
+}
+
+func TestSortStrings(t *testing.T) {
+	tags := []string{"v1.10.0", "v1.9.0", "1.10.0rc1", "v1.2", "1.10.0.post1"}
+	if err := SortStrings(tags); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"v1.2", "v1.9.0", "1.10.0rc1", "v1.10.0", "1.10.0.post1"}
+	if !slices.Equal(tags, want) {
+		t.Errorf("SortStrings = %q, want %q", tags, want)
+	}
+
+	tags = []string{"2.0", "not-a-version", "1.0"}
+	if err := SortStrings(tags); err == nil {
+		t.Error("SortStrings with an invalid tag: expected an error")
+	}
+	if !slices.Equal(tags, []string{"2.0", "not-a-version", "1.0"}) {
+		t.Errorf("SortStrings modified its input on error: %q", tags)
+	}
+}
+
+func TestMaxMin(t *testing.T) {
+	vs := []Version{MustParse("1.0"), MustParse("2.0rc1"), MustParse("2.0.dev0"), MustParse("0.9.post1")}
+	if got := Max(vs...); got.Original != "2.0rc1" {
+		t.Errorf("Max = %q, want %q", got, "2.0rc1")
+	}
+	if got := Min(vs...); got.Original != "0.9.post1" {
+		t.Errorf("Min = %q, want %q", got, "0.9.post1")
+	}
+	if got := Max(MustParse("1.0"), MustParse("1.0.0")); got.Original != "1.0" {
+		t.Errorf("Max of equal versions = %q, want the first", got.Original)
+	}
+	if got := Max(); got.Original != "" || got.Release != nil {
+		t.Errorf("Max() = %+v, want the zero Version", got)
+	}
+}
@@ -0,0 +1,320 @@
+package pyver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidSpecifier is matched by the errors ParseSpecifier returns.
+var ErrInvalidSpecifier = errors.New("invalid specifier")
+
+// Operator is a PEP 440 version comparison operator.
+type Operator string
+
+const (
+	OpCompatible   Operator = "~="
+	OpEqual        Operator = "=="
+	OpNotEqual     Operator = "!="
+	OpLessEqual    Operator = "<="
+	OpGreaterEqual Operator = ">="
+	OpLess         Operator = "<"
+	OpGreater      Operator = ">"
+	OpArbitrary    Operator = "==="
+)
+
+// operators lists the operators longest first, so that a prefix match
+// finds "===" before "==" and "<=" before "<".
+var operators = []Operator{
+	OpArbitrary, OpCompatible, OpEqual, OpNotEqual,
+	OpLessEqual, OpGreaterEqual, OpLess, OpGreater,
+}
+
+// Specifier is a single version clause such as ">=1.0", "~=1.4.2" or
+// "==1.2.*", matching packaging.specifiers.Specifier. The zero value is not
+// a valid specifier; use ParseSpecifier.
+type Specifier struct {
+	op       Operator
+	version  string  // as written, e.g. "1.2.*"
+	v        Version // version without the wildcard; zero for ===
+	wildcard bool    // == or != with a trailing ".*"
+	pre      bool    // whether the clause mentions a pre-release
+}
+
+// ParseSpecifier parses a clause of an operator and a version, surrounded
+// by optional whitespace. Local version labels are only allowed with == and
+// !=, a trailing ".*" only with == and != and a plain release, and ~=
+// needs at least two release components. The === operator accepts any
+// string without whitespace, ';' or ')'.
+func ParseSpecifier(s string) (Specifier, error) {
+	fail := func(reason string) (Specifier, error) {
+		return Specifier{}, fmt.Errorf("%w %q: %s", ErrInvalidSpecifier, s, reason)
+	}
+	t := strings.TrimFunc(s, isSpace)
+	var sp Specifier
+	for _, op := range operators {
+		if strings.HasPrefix(t, string(op)) {
+			sp.op = op
+			break
+		}
+	}
+	if sp.op == "" {
+		return fail("expected one of ~= == != <= >= < > ===")
+	}
+	sp.version = strings.TrimLeftFunc(t[len(sp.op):], isSpace)
+
+	if sp.op == OpArbitrary {
+		if strings.ContainsFunc(sp.version, func(r rune) bool { return isSpace(r) || r == ';' || r == ')' }) {
+			return fail("=== version contains whitespace, ';' or ')'")
+		}
+		// packaging fails on the pre-release check when the string is not
+		// a version; here such a clause simply does not allow them.
+		if v, err := parseGoNative(sp.version); err == nil {
+			sp.pre = v.IsPrerelease()
+		}
+		return sp, nil
+	}
+
+	ver := sp.version
+	if (sp.op == OpEqual || sp.op == OpNotEqual) && strings.HasSuffix(ver, ".*") {
+		sp.wildcard = true
+		ver = ver[:len(ver)-2]
+	}
+	if ver == "" || strings.TrimFunc(ver, isSpace) != ver {
+		return fail("expected a version")
+	}
+	v, err := parseGoNative(ver)
+	if err != nil {
+		return Specifier{}, fmt.Errorf("%w %q: %w", ErrInvalidSpecifier, s, err)
+	}
+	switch {
+	case sp.wildcard && (v.PreKind != "" || v.HasPost || v.HasDev || len(v.Local) > 0):
+		return fail("a prefix match can only follow a release")
+	case len(v.Local) > 0 && sp.op != OpEqual && sp.op != OpNotEqual:
+		return fail(string(sp.op) + " does not allow a local version")
+	case sp.op == OpCompatible && len(v.Release) < 2:
+		return fail("~= needs at least two release components")
+	}
+	sp.v = v
+	sp.pre = sp.op != OpNotEqual && v.IsPrerelease()
+	return sp, nil
+}
+
+// MustParseSpecifier parses a specifier or panics.
+func MustParseSpecifier(s string) Specifier {
+	sp, err := ParseSpecifier(s)
+	if err != nil {
+		panic(err)
+	}
+	return sp
+}
+
+// Operator returns the clause's operator.
+func (s Specifier) Operator() Operator {
+	return s.op
+}
+
+// Version returns the clause's version as written, including a trailing
+// ".*": "==1.2.*" -> "1.2.*".
+func (s Specifier) Version() string {
+	return s.version
+}
+
+// String returns the operator and the version as written, without
+// whitespace: " >= 1.0" -> ">=1.0".
+func (s Specifier) String() string {
+	return string(s.op) + s.version
+}
+
+// PreReleases reports whether the clause admits pre-releases by default,
+// which it does when its version is a pre-release and the operator is not
+// !=, like packaging's Specifier.prereleases: ">=1.0a1" does, ">=1.0"
+// does not.
+func (s Specifier) PreReleases() bool {
+	return s.pre
+}
+
+// Equal reports whether s and o have the same operator and equal versions,
+// like packaging's Specifier.__eq__: "==1.0" equals "== 1.0.0". As ~=
+// depends on the number of release components, "~=1.0" does not equal
+// "~=1.0.0".
+func (s Specifier) Equal(o Specifier) bool {
+	return s.op == o.op && s.canonicalVersion() == o.canonicalVersion()
+}
+
+func (s Specifier) canonicalVersion() string {
+	if s.op == OpCompatible {
+		return canonicalizeNative(s.version, KeepTrailingZeros())
+	}
+	return canonicalizeNative(s.version)
+}
+
+// Contains reports whether v satisfies the clause. Pre-releases only do if
+// PreReleases is true. The rules are those of PEP 440 as implemented by
+// packaging:
+//
+//   - ~=V.N matches >=V.N together with ==V.*.
+//   - == and != ignore v's local label unless the clause has one, and with a
+//     trailing ".*" compare only the leading release components: "1.2.post1"
+//     matches "==1.2.*", as does "1.2+abc".
+//   - <= and >= ignore v's local label.
+//   - <V does not match a pre-release of V's release unless V is itself a
+//     pre-release: "<1.0" rejects "1.0rc1" but not "0.9rc1".
+//   - >V does not match a post-release or a local version of V's release
+//     unless V is itself a post-release: ">1.0" rejects "1.0.post1".
+//   - ===S matches when v's normalized string equals S, ignoring case.
+func (s Specifier) Contains(v Version) bool {
+	return s.contains(v, s.pre)
+}
+
+func (s Specifier) contains(v Version, prereleases bool) bool {
+	if v.IsPrerelease() && !prereleases {
+		return false
+	}
+	switch s.op {
+	case OpCompatible:
+		return compareGoNative(public(v), s.v) >= 0 && prefixMatch(v, compatiblePrefix(s.version))
+	case OpEqual:
+		return s.equal(v)
+	case OpNotEqual:
+		return !s.equal(v)
+	case OpLessEqual:
+		return compareGoNative(public(v), s.v) <= 0
+	case OpGreaterEqual:
+		return compareGoNative(public(v), s.v) >= 0
+	case OpLess:
+		if compareGoNative(v, s.v) >= 0 {
+			return false
+		}
+		return s.v.IsPrerelease() || !v.IsPrerelease() || !sameBase(v, s.v)
+	case OpGreater:
+		if compareGoNative(v, s.v) <= 0 {
+			return false
+		}
+		if !s.v.IsPostrelease() && v.IsPostrelease() && sameBase(v, s.v) {
+			return false
+		}
+		return len(v.Local) == 0 || !sameBase(v, s.v)
+	case OpArbitrary:
+		return strings.ToLower(v.String()) == strings.ToLower(s.version)
+	}
+	return false
+}
+
+func (s Specifier) equal(v Version) bool {
+	if s.wildcard {
+		return prefixMatch(v, strings.TrimSuffix(s.version, ".*"))
+	}
+	if len(s.v.Local) == 0 {
+		v = public(v)
+	}
+	return compareGoNative(v, s.v) == 0
+}
+
+// public returns v without its local label, for comparison only.
+func public(v Version) Version {
+	v.Local = nil
+	return v
+}
+
+// sameBase reports whether two versions have equal epochs and releases.
+func sameBase(a, b Version) bool {
+	return compareGoNative(classOf(a), classOf(b)) == 0
+}
+
+// canonicalizeNative is CanonicalizeString using the native parser.
+func canonicalizeNative(s string, opts ...CanonicalOption) string {
+	v, err := parseGoNative(s)
+	if err != nil {
+		return s
+	}
+	return v.Canonical(opts...)
+}
+
+// The helpers below follow packaging's string-based implementation of ~=
+// and prefix matching, so that unusual spellings behave as they do there.
+
+// prefixMatch reports whether the public part of v starts with the release
+// prefix spec, padding v's release with zeros: "1" matches "1.0".
+func prefixMatch(v Version, spec string) bool {
+	want := versionSplit(canonicalizeNative(spec, KeepTrailingZeros()))
+	got := versionSplit(v.Public())
+	release := func(parts []string) int {
+		n := 0
+		for n < len(parts) && isDigits(parts[n]) {
+			n++
+		}
+		return n
+	}
+	if pad := release(want) - release(got); pad > 0 {
+		n := release(got)
+		got = slices.Concat(got[:n], slices.Repeat([]string{"0"}, pad), got[n:])
+	}
+	return len(got) >= len(want) && slices.Equal(got[:len(want)], want)
+}
+
+// compatiblePrefix returns the prefix ~=V matches: V's release without its
+// last component, with the epoch: "1.4.2rc1" -> "0!1.4".
+func compatiblePrefix(version string) string {
+	parts := versionSplit(version)
+	n := 0
+	for n < len(parts) && !hasSuffixLabel(parts[n]) {
+		n++
+	}
+	parts = parts[:max(n-1, 0)]
+	if len(parts) == 0 {
+		return "!"
+	}
+	return parts[0] + "!" + strings.Join(parts[1:], ".")
+}
+
+// versionSplit splits a version into its epoch and dot-separated parts,
+// separating a pre-release from the release component it is attached to:
+// "1!2.0rc1" -> ["1", "2", "0", "rc1"].
+func versionSplit(version string) []string {
+	epoch, rest := "0", version
+	if i := strings.LastIndexByte(version, '!'); i >= 0 {
+		if epoch, rest = version[:i], version[i+1:]; epoch == "" {
+			epoch = "0"
+		}
+	}
+	parts := []string{epoch}
+	for _, item := range strings.Split(rest, ".") {
+		if n, pre, ok := splitAttachedPre(item); ok {
+			parts = append(parts, n, pre)
+		} else {
+			parts = append(parts, item)
+		}
+	}
+	return parts
+}
+
+// splitAttachedPre splits "0rc1" into "0" and "rc1". The pre-release must
+// be a, b, c or rc followed by digits.
+func splitAttachedPre(item string) (n, pre string, ok bool) {
+	i := 0
+	for i < len(item) && isDigit(item[i]) {
+		i++
+	}
+	if i == 0 {
+		return "", "", false
+	}
+	for _, kind := range []string{"rc", "a", "b", "c"} {
+		if rest, found := strings.CutPrefix(item[i:], kind); found && isDigits(rest) {
+			return item[:i], item[i:], true
+		}
+	}
+	return "", "", false
+}
+
+// hasSuffixLabel reports whether a split part starts a pre-, post- or
+// dev-release segment.
+func hasSuffixLabel(part string) bool {
+	for _, p := range []string{"dev", "a", "b", "rc", "post"} {
+		if strings.HasPrefix(part, p) {
+			return true
+		}
+	}
+	return false
+}
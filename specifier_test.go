@@ -0,0 +1,219 @@
+package pyver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSpecifier(t *testing.T) {
+	tests := []struct {
+		input   string
+		op      Operator
+		version string
+		pre     bool
+	}{
+		{"~=1.4.2", OpCompatible, "1.4.2", false},
+		{"==1.2.*", OpEqual, "1.2.*", false},
+		{"!=2.0", OpNotEqual, "2.0", false},
+		{"<=3", OpLessEqual, "3", false},
+		{">=1.0a1", OpGreaterEqual, "1.0a1", true},
+		{"<2", OpLess, "2", false},
+		{">1!0", OpGreater, "1!0", false},
+		{"===foobar", OpArbitrary, "foobar", false},
+		{"===1.0rc1", OpArbitrary, "1.0rc1", true},
+		{"===", OpArbitrary, "", false},
+		{"====1", OpArbitrary, "=1", false},
+		{" >=  1.0 ", OpGreaterEqual, "1.0", false},
+		{"==V1.0-RC1", OpEqual, "V1.0-RC1", true},
+		{"!=1.0rc1", OpNotEqual, "1.0rc1", false},
+		{"==1.0+Local.1", OpEqual, "1.0+Local.1", false},
+		{"~=1.0.dev1", OpCompatible, "1.0.dev1", true},
+		{"==1!1.*", OpEqual, "1!1.*", false},
+	}
+	for _, tc := range tests {
+		sp, err := ParseSpecifier(tc.input)
+		if err != nil {
+			t.Errorf("ParseSpecifier(%q): %v", tc.input, err)
+			continue
+		}
+		if sp.Operator() != tc.op || sp.Version() != tc.version || sp.PreReleases() != tc.pre {
+			t.Errorf("ParseSpecifier(%q) = %q %q pre=%v, want %q %q pre=%v", tc.input, sp.Operator(), sp.Version(), sp.PreReleases(), tc.op, tc.version, tc.pre)
+		}
+		if want := string(tc.op) + tc.version; sp.String() != want {
+			t.Errorf("ParseSpecifier(%q).String() = %q, want %q", tc.input, sp, want)
+		}
+	}
+}
+
+var invalidSpecifiers = []string{
+	"", "1.0", "=1.0", "lolwat", ">==1", "< =1", "==", ">=",
+	"~=1", "~=1.0.*", "~=1.0+abc", ">=1.0+abc", ">=1.0.*", "<1.*",
+	"==1.0a1.*", "==1.0.post1.*", "==1.0.*+abc", "==1.0.*.*", "==1.0 .*",
+	"==1.0. *", "==1.0 1", "===a b", "===a;", "===a)", "==1..0", ">=1.0,<2",
+}
+
+func TestParseSpecifierInvalid(t *testing.T) {
+	for _, s := range invalidSpecifiers {
+		if sp, err := ParseSpecifier(s); !errors.Is(err, ErrInvalidSpecifier) {
+			t.Errorf("ParseSpecifier(%q) = %q, %v, want ErrInvalidSpecifier", s, sp, err)
+		}
+	}
+	_, err := ParseSpecifier(">=1.0@")
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Input != "1.0@" {
+		t.Errorf("ParseSpecifier(bad version) = %v, want a ParseError for the version", err)
+	}
+}
+
+var specifierContainsCases = []struct {
+	spec, version string
+	want          bool
+}{
+	{"~=1.4.2", "1.4.2", true},
+	{"~=1.4.2", "1.4.9", true},
+	{"~=1.4.2", "1.5", false},
+	{"~=1.4", "1.9", true},
+	{"~=1.4", "2.0", false},
+	{"~=1.4.2rc1", "1.4.2", true},
+	{"~=1.4rc1", "1.5", true},
+	{"~=1.4.post1", "1.4", false},
+	{"~=1!1.4", "1.9", false},
+	{"~=1!1.4", "1!1.9", true},
+	// packaging only recognizes lower-case pre-release labels here, and
+	// cannot split a "v" prefix from the release.
+	{"~=1.4.RC1", "1.5", false},
+	{"~=1.4.rc1", "1.5", true},
+	{"~=v1.4", "1.4", false},
+
+	{"==1.2.*", "1.2", true},
+	{"==1.2.*", "1.2.9", true},
+	{"==1.2.*", "1.2.post1", true},
+	{"==1.2.*", "1.2+abc", true},
+	{"==1.2.*", "1.3", false},
+	{"==1.0.*", "1", true},
+	{"==1.0.0.*", "1", true},
+	{"==1.*", "1.0rc1", false},
+	{"==1!1.*", "1!1.5", true},
+	{"==1!1.*", "1.5", false},
+	{"!=1.0.*", "1.0.1", false},
+	{"!=1.0.*", "1.1", true},
+
+	{"==1.0", "1.0.0", true},
+	{"==1.0", "1.0+abc", true},
+	{"==1.0+abc", "1.0+ABC", true},
+	{"==1.0+abc", "1.0", false},
+	{"==1.0+abc", "1.0+abd", false},
+	{"==1.0rc1", "1.0rc1", true},
+	{"!=2.0", "2.0", false},
+	{"!=2.0", "2.0+abc", false},
+	{"!=2.0", "2.0.1", true},
+	{"!=2.0rc1", "2.0rc1", false},
+
+	{"<=3", "3.0+abc", true},
+	{"<=3", "3.0.post1", false},
+	{">=1.0", "1.0+abc", true},
+	{">=1.0", "1.1a1", false},
+	{">=1.0a1", "1.1a1", true},
+
+	{"<2", "1.9", true},
+	{"<2", "2.0rc1", false},
+	{"<2", "2.0.dev1", false},
+	{"<2", "1.9rc1", false},
+	{"<2rc1", "2.0a1", true},
+	{"<2", "2.0+abc", false},
+	{">1.0", "1.0.post1", false},
+	{">1.0", "1.0+abc", false},
+	{">1.0", "1.0.1", true},
+	{">1.0", "1.0.1+abc", true},
+	{">1.0.post1", "1.0.post2", true},
+	{">1.0.post1", "1.0.post1+abc", false},
+
+	{"===1.0", "1.0", true},
+	{"===1.0", "1.0.0", false},
+	{"===1.0+ABC", "1.0+abc", true},
+	{"===foobar", "1.0", false},
+}
+
+func TestSpecifierContains(t *testing.T) {
+	for _, tc := range specifierContainsCases {
+		sp := MustParseSpecifier(tc.spec)
+		if got := sp.Contains(MustParse(tc.version)); got != tc.want {
+			t.Errorf("%q.Contains(%q) = %v, want %v", tc.spec, tc.version, got, tc.want)
+		}
+	}
+}
+
+func TestSpecifierEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"==1.2.3", "== 1.2.3.0", true},
+		{"==1.2.3", "==1.2.4", false},
+		{"==1.2.3", "~=1.2.3", false},
+		{"~=1.0", "~=1.0.0", false},
+		{">=v1.0", ">=1", true},
+		{"==1.0.*", "==1.0.0.*", false},
+		{"===foo", "===foo", true},
+	}
+	for _, tc := range tests {
+		if got := MustParseSpecifier(tc.a).Equal(MustParseSpecifier(tc.b)); got != tc.want {
+			t.Errorf("%q.Equal(%q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+// specifierInputs combines every operator with assorted versions; some of
+// the combinations are invalid.
+func specifierInputs() []string {
+	versions := []string{
+		"1", "1.0", "1.0.0", "2.0", "1.4.2", "1.0rc1", "1.0.post1", "1.0.dev1",
+		"1.0a1.post1.dev1", "1!1.0", "1.0+abc", "1.*", "1.0.*", "1!1.*", "v1.4",
+		"1.4.RC1", "1.4.c1", "1.4-1", "1.4_dev", "1.0b2-346",
+	}
+	var specs []string
+	for _, op := range operators {
+		for _, v := range versions {
+			specs = append(specs, string(op)+v)
+		}
+	}
+	return append(specs, invalidSpecifiers...)
+}
+
+func TestSpecifierParity(t *testing.T) {
+	w := requireDifferentialWorker(t)
+	var versions []string
+	versions = append(versions, orderedVersions...)
+	versions = append(versions, "0.9", "1", "1.0.0", "1.0+abc.5", "1.0.post1+abc", "1!1.0", "1.4.9", "1.5", "2.0", "2.0rc1")
+	for _, s := range specifierInputs() {
+		sp, err := ParseSpecifier(s)
+		for _, v := range versions {
+			res, backendErr := w.call(context.Background(), w.logger(), "contains", s, v)
+			if backendErr != nil {
+				if !strings.Contains(backendErr.Error(), "InvalidSpecifier") {
+					// packaging fails on === with a string that is not a
+					// version.
+					continue
+				}
+				if err == nil {
+					t.Errorf("ParseSpecifier(%q) succeeded, packaging rejects it", s)
+				}
+				break
+			}
+			if err != nil {
+				t.Errorf("ParseSpecifier(%q): %v, packaging accepts it", s, err)
+				break
+			}
+			var want bool
+			if err := json.Unmarshal(res, &want); err != nil {
+				t.Fatal(err)
+			}
+			if got := sp.Contains(MustParse(v)); got != want {
+				t.Errorf("%q.Contains(%q) = %v, packaging gives %v", s, v, got, want)
+			}
+		}
+	}
+}
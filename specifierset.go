@@ -0,0 +1,181 @@
+package pyver
+
+import (
+	"slices"
+	"strings"
+)
+
+// PreReleasePolicy controls whether a SpecifierSet admits pre-releases,
+// like the prereleases argument of packaging's SpecifierSet.
+type PreReleasePolicy int
+
+const (
+	// PreReleasesAuto admits pre-releases if a clause mentions one, as in
+	// ">=1.0rc1"; Filter on an empty set also falls back to them when
+	// there is nothing else.
+	PreReleasesAuto PreReleasePolicy = iota
+	// PreReleasesInclude always admits pre-releases.
+	PreReleasesInclude
+	// PreReleasesExclude never admits pre-releases.
+	PreReleasesExclude
+)
+
+func (p PreReleasePolicy) String() string {
+	switch p {
+	case PreReleasesInclude:
+		return "include"
+	case PreReleasesExclude:
+		return "exclude"
+	}
+	return "auto"
+}
+
+// SpecifierSet is a conjunction of specifiers such as ">=1.0,!=1.3.*,<2",
+// matching packaging.specifiers.SpecifierSet. The zero value is the empty
+// set, which every final release satisfies.
+type SpecifierSet struct {
+	specs  []Specifier
+	policy PreReleasePolicy
+}
+
+// ParseSpecifierSet parses comma-separated specifiers. Empty clauses are
+// ignored, so "" is the empty set, and a clause equal to an earlier one
+// (see Specifier.Equal) is dropped.
+func ParseSpecifierSet(s string) (SpecifierSet, error) {
+	var set SpecifierSet
+	for clause := range strings.SplitSeq(s, ",") {
+		if strings.TrimFunc(clause, isSpace) == "" {
+			continue
+		}
+		sp, err := ParseSpecifier(clause)
+		if err != nil {
+			return SpecifierSet{}, err
+		}
+		set.add(sp)
+	}
+	return set, nil
+}
+
+// MustParseSpecifierSet parses a specifier set or panics.
+func MustParseSpecifierSet(s string) SpecifierSet {
+	set, err := ParseSpecifierSet(s)
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+// NewSpecifierSet returns the set of the given specifiers.
+func NewSpecifierSet(specs ...Specifier) SpecifierSet {
+	var set SpecifierSet
+	for _, sp := range specs {
+		set.add(sp)
+	}
+	return set
+}
+
+func (s *SpecifierSet) add(sp Specifier) {
+	if !slices.ContainsFunc(s.specs, sp.Equal) {
+		s.specs = append(s.specs, sp)
+	}
+}
+
+// WithPreReleases returns s with the given pre-release policy.
+func (s SpecifierSet) WithPreReleases(p PreReleasePolicy) SpecifierSet {
+	s.policy = p
+	return s
+}
+
+// PreReleasePolicy returns the policy set with WithPreReleases.
+func (s SpecifierSet) PreReleasePolicy() PreReleasePolicy {
+	return s.policy
+}
+
+// Specifiers returns the clauses of s in the order they were given.
+func (s SpecifierSet) Specifiers() []Specifier {
+	return slices.Clone(s.specs)
+}
+
+// Len returns the number of clauses.
+func (s SpecifierSet) Len() int {
+	return len(s.specs)
+}
+
+// String returns the clauses sorted and joined by commas, like packaging:
+// ">=1.0, !=1.3" -> "!=1.3,>=1.0".
+func (s SpecifierSet) String() string {
+	clauses := make([]string, len(s.specs))
+	for i, sp := range s.specs {
+		clauses[i] = sp.String()
+	}
+	slices.Sort(clauses)
+	return strings.Join(clauses, ",")
+}
+
+// Equal reports whether s and o have equal clauses, ignoring their order
+// and pre-release policies.
+func (s SpecifierSet) Equal(o SpecifierSet) bool {
+	return len(s.specs) == len(o.specs) && !slices.ContainsFunc(s.specs, func(sp Specifier) bool {
+		return !slices.ContainsFunc(o.specs, sp.Equal)
+	})
+}
+
+// PreReleases reports whether s admits pre-releases: always or never if
+// its policy says so, and otherwise if any clause mentions one (see
+// Specifier.PreReleases).
+func (s SpecifierSet) PreReleases() bool {
+	pre, _ := s.preReleases()
+	return pre
+}
+
+// preReleases also reports whether the answer is known; with the auto
+// policy an empty set leaves it open, like packaging's None.
+func (s SpecifierSet) preReleases() (pre, known bool) {
+	switch s.policy {
+	case PreReleasesInclude:
+		return true, true
+	case PreReleasesExclude:
+		return false, true
+	}
+	return slices.ContainsFunc(s.specs, Specifier.PreReleases), len(s.specs) > 0
+}
+
+// Contains reports whether v satisfies every clause. A pre-release only
+// does if PreReleases is true, so the empty set contains every final
+// release and, unless pre-releases are included, no pre-release.
+func (s SpecifierSet) Contains(v Version) bool {
+	pre := s.PreReleases()
+	if v.IsPrerelease() && !pre {
+		return false
+	}
+	for _, sp := range s.specs {
+		if !sp.contains(v, pre) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the versions in vs that s contains, in their original
+// order. As in packaging, an empty set with the auto policy returns the
+// pre-releases in vs when it holds nothing else:
+//
+//	"" filters [1.5a1 2.0a1] to [1.5a1 2.0a1]
+//	"" filters [1.0 1.5a1] to [1.0]
+//	">=1.0" filters [1.5a1] to []
+func (s SpecifierSet) Filter(vs []Version) []Version {
+	var out []Version
+	for _, v := range vs {
+		if s.Contains(v) {
+			out = append(out, v)
+		}
+	}
+	if _, known := s.preReleases(); len(out) == 0 && !known {
+		for _, v := range vs {
+			if v.IsPrerelease() {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,176 @@
+package pyver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestParseSpecifierSet(t *testing.T) {
+	tests := []struct {
+		input, want string
+		n           int
+	}{
+		{"", "", 0},
+		{" , ", "", 0},
+		{">=1.0,!=1.3.*,<2", "!=1.3.*,<2,>=1.0", 3},
+		{" >= 1.0 , , <2 ", "<2,>=1.0", 2},
+		{"==1.0,==1.0.0,>=2", "==1.0,>=2", 2},
+		{"~=1.0,~=1.0.0", "~=1.0,~=1.0.0", 2},
+	}
+	for _, tc := range tests {
+		set, err := ParseSpecifierSet(tc.input)
+		if err != nil {
+			t.Errorf("ParseSpecifierSet(%q): %v", tc.input, err)
+			continue
+		}
+		if set.String() != tc.want || set.Len() != tc.n {
+			t.Errorf("ParseSpecifierSet(%q) = %q with %d clauses, want %q with %d", tc.input, set, set.Len(), tc.want, tc.n)
+		}
+	}
+	for _, s := range []string{">=1;<2", ">=1 <2", ">=1,lolwat", "1.0"} {
+		if _, err := ParseSpecifierSet(s); !errors.Is(err, ErrInvalidSpecifier) {
+			t.Errorf("ParseSpecifierSet(%q) = %v, want ErrInvalidSpecifier", s, err)
+		}
+	}
+}
+
+func TestSpecifierSetContains(t *testing.T) {
+	tests := []struct {
+		set     string
+		policy  PreReleasePolicy
+		version string
+		want    bool
+	}{
+		{">=1.0,!=1.3.*,<2", PreReleasesAuto, "1.2", true},
+		{">=1.0,!=1.3.*,<2", PreReleasesAuto, "1.3.1", false},
+		{">=1.0,!=1.3.*,<2", PreReleasesAuto, "2.0", false},
+		{">=1.0,!=1.3.*,<2", PreReleasesAuto, "1.5a1", false},
+		{">=1.0,!=1.3.*,<2", PreReleasesInclude, "1.5a1", true},
+		{">=1.0a1,<2", PreReleasesAuto, "1.5a1", true},
+		{">=1.0a1,<2", PreReleasesExclude, "1.5a1", false},
+		{">=1.0,!=1.5a1", PreReleasesAuto, "1.6a1", false},
+		{"", PreReleasesAuto, "1.0", true},
+		{"", PreReleasesAuto, "1.0a1", false},
+		{"", PreReleasesInclude, "1.0a1", true},
+		{"", PreReleasesExclude, "1.0", true},
+	}
+	for _, tc := range tests {
+		set := MustParseSpecifierSet(tc.set).WithPreReleases(tc.policy)
+		if got := set.Contains(MustParse(tc.version)); got != tc.want {
+			t.Errorf("%q (%v).Contains(%q) = %v, want %v", tc.set, tc.policy, tc.version, got, tc.want)
+		}
+	}
+}
+
+func TestSpecifierSetFilter(t *testing.T) {
+	tests := []struct {
+		set      string
+		policy   PreReleasePolicy
+		versions []string
+		want     []string
+	}{
+		{">=1.2.3", PreReleasesAuto, []string{"1.2", "1.3", "1.5a1"}, []string{"1.3"}},
+		{">=1.2.3", PreReleasesAuto, []string{"1.2", "1.5a1"}, nil},
+		{">=1.2.3", PreReleasesInclude, []string{"1.3", "1.5a1"}, []string{"1.3", "1.5a1"}},
+		{">=1.2.3a1", PreReleasesAuto, []string{"1.3", "1.5a1"}, []string{"1.3", "1.5a1"}},
+		{"", PreReleasesAuto, []string{"1.3", "1.5a1"}, []string{"1.3"}},
+		{"", PreReleasesAuto, []string{"1.5a1", "1.3"}, []string{"1.3"}},
+		{"", PreReleasesAuto, []string{"1.5a1", "2.0.dev1"}, []string{"1.5a1", "2.0.dev1"}},
+		{"", PreReleasesExclude, []string{"1.5a1"}, nil},
+		{"", PreReleasesInclude, []string{"1.3", "1.5a1"}, []string{"1.3", "1.5a1"}},
+	}
+	for _, tc := range tests {
+		set := MustParseSpecifierSet(tc.set).WithPreReleases(tc.policy)
+		var got []string
+		for _, v := range set.Filter(parseAll(tc.versions)) {
+			got = append(got, v.Original)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%q (%v).Filter(%q) = %q, want %q", tc.set, tc.policy, tc.versions, got, tc.want)
+		}
+	}
+}
+
+func TestSpecifierSetEqual(t *testing.T) {
+	a := MustParseSpecifierSet(">=1.0,!=1.5")
+	if !a.Equal(MustParseSpecifierSet("!=1.5.0, >=1").WithPreReleases(PreReleasesInclude)) {
+		t.Error("sets with the same clauses are not equal")
+	}
+	if a.Equal(MustParseSpecifierSet(">=1.0")) || a.Equal(MustParseSpecifierSet(">=1.0,!=1.6")) {
+		t.Error("sets with different clauses are equal")
+	}
+	if !NewSpecifierSet(MustParseSpecifier(">=1.0"), MustParseSpecifier("!=1.5")).Equal(a) {
+		t.Error("NewSpecifierSet differs from the parsed set")
+	}
+}
+
+func parseAll(ss []string) []Version {
+	vs := make([]Version, len(ss))
+	for i, s := range ss {
+		vs[i] = MustParse(s)
+	}
+	return vs
+}
+
+func TestSpecifierSetParity(t *testing.T) {
+	w := requireDifferentialWorker(t)
+	var clauses []string
+	for _, s := range specifierInputs() {
+		if sp, err := ParseSpecifier(s); err == nil && sp.Operator() != OpArbitrary {
+			clauses = append(clauses, s)
+		}
+	}
+	versions := append(slices.Clone(orderedVersions), "0.9", "1", "1.0+abc.5", "1!1.0", "1.4.9", "1.5", "2.0", "2.0rc1")
+	policies := map[PreReleasePolicy]string{PreReleasesAuto: "", PreReleasesInclude: "true", PreReleasesExclude: "false"}
+
+	r := rand.New(rand.NewSource(1))
+	for range 300 {
+		var s string
+		for i := range r.Intn(4) {
+			if i > 0 {
+				s += ","
+			}
+			s += clauses[r.Intn(len(clauses))]
+		}
+		// A random selection of versions, some of only pre-releases.
+		var sample []string
+		pre := r.Intn(3) == 0
+		for _, v := range versions {
+			if r.Intn(4) == 0 && (!pre || MustParse(v).IsPrerelease()) {
+				sample = append(sample, v)
+			}
+		}
+		for policy, arg := range policies {
+			set := MustParseSpecifierSet(s).WithPreReleases(policy)
+			res, err := w.call(context.Background(), w.logger(), "filter", append([]string{s, arg}, sample...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []string
+			if err := json.Unmarshal(res, &want); err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, v := range set.Filter(parseAll(sample)) {
+				got = append(got, v.Original)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("%q (%v).Filter(%q) = %q, packaging gives %q", s, policy, sample, got, want)
+			}
+			for _, v := range sample {
+				res, err := w.call(context.Background(), w.logger(), "satisfies", s, arg, v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := set.Contains(MustParse(v)); string(res) != strconv.FormatBool(got) {
+					t.Errorf("%q (%v).Contains(%q) = %v, packaging gives %s", s, policy, v, got, res)
+				}
+			}
+		}
+	}
+}
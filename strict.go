@@ -0,0 +1,121 @@
+package pyver
+
+import "errors"
+
+// ErrNotCanonical is matched by the *ParseError ParseStrict returns for a
+// valid version that is not in normalized form.
+var ErrNotCanonical = errors.New("version not in canonical form")
+
+// ReasonNotCanonical is the Reason of a ParseError for a valid version
+// that is not in normalized form.
+const ReasonNotCanonical Reason = "not-canonical"
+
+// Normalization identifies one of the PEP 440 normalization rules that
+// Parse applies to lenient spellings.
+type Normalization uint8
+
+const (
+	NormWhitespace     Normalization = iota // leading or trailing whitespace is removed
+	NormCase                                // letters are lower-cased
+	NormVPrefix                             // a leading "v" is removed
+	NormZeroEpoch                           // an explicit "0!" epoch is removed
+	NormLeadingZeros                        // leading zeros are removed from numerals
+	NormPreSpelling                         // "alpha", "beta", "c", "pre", "preview" become "a", "b", "rc"
+	NormPreSeparator                        // separators around a pre-release are removed
+	NormPreNumber                           // a missing pre-release number becomes 0
+	NormPostSpelling                        // "rev" and "r" become "post"
+	NormPostSeparator                       // a post-release is introduced by a single "."
+	NormImplicitPost                        // "-N" becomes ".postN"
+	NormPostNumber                          // a missing post-release number becomes 0
+	NormDevSeparator                        // a dev-release is introduced by a single "."
+	NormDevNumber                           // a missing dev-release number becomes 0
+	NormLocalSeparator                      // "-" and "_" in a local label become "."
+	numNormalizations
+)
+
+var normalizationNames = [numNormalizations]string{
+	NormWhitespace:     "whitespace",
+	NormCase:           "case",
+	NormVPrefix:        "v-prefix",
+	NormZeroEpoch:      "zero-epoch",
+	NormLeadingZeros:   "leading-zeros",
+	NormPreSpelling:    "pre-spelling",
+	NormPreSeparator:   "pre-separator",
+	NormPreNumber:      "pre-number",
+	NormPostSpelling:   "post-spelling",
+	NormPostSeparator:  "post-separator",
+	NormImplicitPost:   "implicit-post",
+	NormPostNumber:     "post-number",
+	NormDevSeparator:   "dev-separator",
+	NormDevNumber:      "dev-number",
+	NormLocalSeparator: "local-separator",
+}
+
+// String returns the machine-readable name of the rule.
+func (n Normalization) String() string {
+	if n < numNormalizations {
+		return normalizationNames[n]
+	}
+	return "unknown"
+}
+
+// normSet is a set of Normalization rules.
+type normSet uint32
+
+// list returns the rules in the set in declaration order.
+func (s normSet) list() []Normalization {
+	var out []Normalization
+	for n := Normalization(0); n < numNormalizations; n++ {
+		if s&(1<<n) != 0 {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// suffixRules maps each suffix segment to the rules governing it. A
+// segment with a single spelling, like the dev-release, has no canonical
+// set and no spelling rule.
+var suffixRules = map[Segment]struct {
+	canonical                   map[string]bool
+	spelling, separator, number Normalization
+}{
+	SegmentPre:  {map[string]bool{"a": true, "b": true, "rc": true}, NormPreSpelling, NormPreSeparator, NormPreNumber},
+	SegmentPost: {map[string]bool{"post": true}, NormPostSpelling, NormPostSeparator, NormPostNumber},
+	SegmentDev:  {separator: NormDevSeparator, number: NormDevNumber},
+}
+
+// ParseStrict parses s like Parse but accepts only versions that are
+// already in normalized form, such as "1.0rc1" but not "1.0-RC1".
+//
+// For a valid but non-canonical input it returns the parsed Version along
+// with a *ParseError whose Err is ErrNotCanonical, whose Offset and Segment
+// locate the first offending spot, and whose Normalizations lists every rule
+// Parse applied.
+func ParseStrict(s string) (Version, error) {
+	return (*Parser)(nil).ParseStrict(s)
+}
+
+// ParseStrict is like the package-level ParseStrict but uses the settings
+// of p.
+func (p *Parser) ParseStrict(s string) (Version, error) {
+	v, err := p.Parse(s)
+	if err != nil {
+		return v, err
+	}
+	sc := scanner{in: s, seg: SegmentRelease}
+	if err := sc.scan(); err != nil {
+		return v, err
+	}
+	if sc.norms == 0 {
+		return v, nil
+	}
+	return v, &ParseError{
+		Input:          s,
+		Offset:         sc.normAt,
+		Segment:        sc.normSeg,
+		Reason:         ReasonNotCanonical,
+		Err:            ErrNotCanonical,
+		Normalizations: sc.norms.list(),
+	}
+}
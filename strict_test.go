@@ -0,0 +1,94 @@
+package pyver
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseStrictCanonical(t *testing.T) {
+	cases := []string{
+		"1.0", "0.0.1", "1!1.0", "1.0a0", "1.0b2", "1.0rc1", "1.0.post0", "1.0.dev3",
+		"1.0a1.post2.dev3", "1.0+abc.5", "2!3.4.5a1.post2.dev3+meta",
+	}
+	for _, s := range cases {
+		t.Run(s, func(t *testing.T) {
+			v, err := ParseStrict(s)
+			if err != nil {
+				t.Fatalf("ParseStrict(%q): %v", s, err)
+			}
+			if v.String() != s {
+				t.Errorf("normalized: got %q, want %q", v.String(), s)
+			}
+		})
+	}
+}
+
+func TestParseStrictNonCanonical(t *testing.T) {
+	cases := []struct {
+		input  string
+		offset int
+		rules  []Normalization
+	}{
+		{" 1.0", 0, []Normalization{NormWhitespace}},
+		{"1.0\n", 3, []Normalization{NormWhitespace}},
+		{"v1.0", 0, []Normalization{NormVPrefix}},
+		{"V1.0RC1", 0, []Normalization{NormCase, NormVPrefix}},
+		{"0!1.0", 0, []Normalization{NormZeroEpoch}},
+		{"01!1.0", 0, []Normalization{NormLeadingZeros}},
+		{"1.02", 2, []Normalization{NormLeadingZeros}},
+		{"1.0alpha1", 3, []Normalization{NormPreSpelling}},
+		{"1.0c1", 3, []Normalization{NormPreSpelling}},
+		{"1.0-rc1", 3, []Normalization{NormPreSeparator}},
+		{"1.0rc.1", 5, []Normalization{NormPreSeparator}},
+		{"1.0a", 4, []Normalization{NormPreNumber}},
+		{"1.0a01", 4, []Normalization{NormLeadingZeros}},
+		{"1.0.rev1", 4, []Normalization{NormPostSpelling}},
+		{"1.0post1", 3, []Normalization{NormPostSeparator}},
+		{"1.0_post1", 3, []Normalization{NormPostSeparator}},
+		{"1.0.post-1", 8, []Normalization{NormPostSeparator}},
+		{"1.0-1", 3, []Normalization{NormImplicitPost}},
+		{"1.0.post", 8, []Normalization{NormPostNumber}},
+		{"1.0dev1", 3, []Normalization{NormDevSeparator}},
+		{"1.0.dev", 7, []Normalization{NormDevNumber}},
+		{"1.0.DEV1", 4, []Normalization{NormCase}},
+		{"1.0+abc-def_1", 7, []Normalization{NormLocalSeparator}},
+		{"1.0+ABC", 4, []Normalization{NormCase}},
+		{"1.0+007", 4, []Normalization{NormLeadingZeros}},
+		{"v1.0-Preview_2-r_3dev+Abc_01", 0, []Normalization{
+			NormCase, NormVPrefix, NormLeadingZeros, NormPreSpelling, NormPreSeparator,
+			NormPostSpelling, NormPostSeparator, NormDevSeparator, NormDevNumber, NormLocalSeparator,
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseStrict(tc.input)
+			if !errors.Is(err, ErrNotCanonical) {
+				t.Fatalf("expected ErrNotCanonical, got %v", err)
+			}
+			if errors.Is(err, ErrInvalidVersion) {
+				t.Errorf("non-canonical error must not match ErrInvalidVersion")
+			}
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected *ParseError, got %T", err)
+			}
+			if pe.Reason != ReasonNotCanonical || pe.Offset != tc.offset {
+				t.Errorf("reason/offset: got %s/%d, want %s/%d", pe.Reason, pe.Offset, ReasonNotCanonical, tc.offset)
+			}
+			if !reflect.DeepEqual(pe.Normalizations, tc.rules) {
+				t.Errorf("rules: got %v, want %v", pe.Normalizations, tc.rules)
+			}
+			if v.Normalized == "" {
+				t.Errorf("expected the parsed version alongside the error")
+			}
+		})
+	}
+}
+
+func TestParseStrictInvalid(t *testing.T) {
+	_, err := ParseStrict("1..0")
+	if !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("expected ErrInvalidVersion, got %v", err)
+	}
+}
@@ -2,14 +2,93 @@
 package pyver
 
 // Version represents a parsed PEP 440 version.
+//
+// A pre-release segment is present when PreKind is non-empty. Post- and
+// dev-release segments carry an explicit presence flag so that "1.0.post0"
+// and "1.0.dev0" are distinguishable from "1.0".
+//
+// PEP 440 puts no bound on numeric segments. A numeral too large for an int
+// is stored as math.MaxInt in its field while its exact digits are kept
+// internally, so normalization and comparison remain correct.
 type Version struct {
 	Epoch      int      // e.g. 1!1.2.3 -> 1
 	Release    []int    // e.g. 1.2.3 -> [1,2,3]
 	PreKind    string   // "a", "b", "rc", or "" if not present
 	PreNum     int      // e.g. "a1" -> 1, 0 if not present
+	HasPost    bool     // true if a post-release segment is present (including "post0")
 	PostNum    int      // e.g. "post2" -> 2, 0 if not present
+	HasDev     bool     // true if a dev-release segment is present (including "dev0")
 	DevNum     int      // e.g. "dev3" -> 3, 0 if not present
 	Local      []string // e.g. "abc.1" -> ["abc", "1"]
 	Original   string   // original version string
 	Normalized string   // canonical/normalized version string
+
+	wide *wideNumerals // digits of numerals that overflow their int field
+}
+
+// Public returns the normalized version without its local label, like
+// packaging's Version.public: "1!1.2.3.dev1+abc" -> "1!1.2.3.dev1".
+func (v Version) Public() string {
+	v.Local = nil
+	return versionToString(v)
+}
+
+// BaseVersion returns the epoch and release only, like packaging's
+// Version.base_version: "1!1.2.3rc1.post2+abc" -> "1!1.2.3".
+func (v Version) BaseVersion() string {
+	return versionToString(Version{Epoch: v.Epoch, Release: v.Release, wide: v.wide})
+}
+
+// IsPrerelease reports whether v is a pre-release or a development release.
+func (v Version) IsPrerelease() bool {
+	return v.PreKind != "" || v.HasDev
+}
+
+// IsPostrelease reports whether v has a post-release segment.
+func (v Version) IsPostrelease() bool {
+	return v.HasPost
+}
+
+// IsDevrelease reports whether v has a development release segment.
+func (v Version) IsDevrelease() bool {
+	return v.HasDev
+}
+
+// Major returns the first release component, or 0 if there is none.
+func (v Version) Major() int {
+	return v.releaseAt(0)
+}
+
+// Minor returns the second release component, or 0 if there is none.
+func (v Version) Minor() int {
+	return v.releaseAt(1)
+}
+
+// Micro returns the third release component, or 0 if there is none.
+func (v Version) Micro() int {
+	return v.releaseAt(2)
+}
+
+// Compare returns -1, 0 or 1 as v is less than, equal to or greater than o,
+// like the package-level Compare.
+func (v Version) Compare(o Version) int {
+	return Compare(v, o)
+}
+
+// Less reports whether v sorts before o.
+func (v Version) Less(o Version) bool {
+	return Compare(v, o) < 0
+}
+
+// Equal reports whether v and o are equal under PEP 440, so "1.0" equals
+// "1.0.0". Local labels are significant: "1.0+abc" does not equal "1.0".
+func (v Version) Equal(o Version) bool {
+	return Compare(v, o) == 0
+}
+
+func (v Version) releaseAt(i int) int {
+	if i < len(v.Release) {
+		return v.Release[i]
+	}
+	return 0
 }
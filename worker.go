@@ -0,0 +1,457 @@
+package pyver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Errors returned by a Worker. Each matches ErrBackend.
+var (
+	// ErrWorkerClosed is returned for requests to a Worker after Close.
+	ErrWorkerClosed = fmt.Errorf("%w: worker closed", ErrBackend)
+
+	// ErrPythonUnavailable means the Python interpreter, or the command
+	// given in GO_PYTHON, could not be found.
+	ErrPythonUnavailable = fmt.Errorf("%w: python interpreter not found", ErrBackend)
+
+	// ErrPackagingUnavailable means the interpreter runs but cannot import
+	// the packaging library.
+	ErrPackagingUnavailable = fmt.Errorf("%w: packaging module not available", ErrBackend)
+)
+
+// packagingMissingExit is the exit status of pyver_backend.py when it
+// cannot import packaging.
+const packagingMissingExit = 4
+
+// workerStopTimeout bounds how long Close waits for the Python process to
+// exit after its input is closed before killing it.
+const workerStopTimeout = 5 * time.Second
+
+// Worker is a long-lived Python backend process. It runs pyver_backend.py
+// in serve mode and exchanges JSON lines with it, so the interpreter start-up
+// cost is paid once rather than per call. A Worker is safe for concurrent
+// use; responses are matched to requests by id.
+//
+// The process is started by Start or on the first request. If it exits
+// unexpectedly, requests in flight fail with ErrBackend and the next
+// request starts a new process. The process is also restarted when
+// GO_PYTHON or BackendPath change.
+//
+// ParseContext, CompareContext and SortStringsContext stop waiting when
+// their context is done. The process is shared, so it is kept for the
+// other requests unless it has not answered anything since the abandoned
+// request was written to it. Such a process is taken to hang, like an
+// interpreter installing packaging without network access, and is killed
+// so that the next request starts afresh.
+//
+// The zero value is ready to use. With USE_GO_NATIVE=0 the package-level
+// functions use a shared Worker, so most programs never need to create one.
+type Worker struct {
+	// Logger receives diagnostics for this Worker. If nil, the logger
+	// installed with SetLogger is used.
+	Logger *slog.Logger
+
+	mu     sync.Mutex
+	proc   *workerProc
+	nextID uint64
+	closed bool
+}
+
+// defaultWorker serves backend mode.
+var defaultWorker = &Worker{}
+
+type workerRequest struct {
+	ID   uint64   `json:"id"`
+	Op   string   `json:"op"`
+	Args []string `json:"args"`
+}
+
+type workerResponse struct {
+	ID      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Invalid *string         `json:"invalid"`
+	Error   string          `json:"error"`
+}
+
+// workerProc is one run of the Python process.
+type workerProc struct {
+	args     []string // command line, to notice configuration changes
+	cmd      *exec.Cmd
+	kill     context.CancelFunc // kills the process
+	writes   chan []byte        // request lines for the writer goroutine
+	answered atomic.Uint64      // number of responses read
+	stopping chan struct{}      // closed to close the process's input
+	stderr   bytes.Buffer
+	done     chan struct{} // closed once the process has exited
+	err      error         // why the process exited; set before done is closed
+
+	mu      sync.Mutex
+	pending map[uint64]chan workerResponse
+}
+
+func (w *Worker) logger() *slog.Logger {
+	return (&Parser{Logger: w.Logger}).logger()
+}
+
+// Start starts the Python process if it is not running, and re-enables a
+// Worker stopped with Close.
+func (w *Worker) Start() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = false
+	_, err := w.running(w.logger())
+	return err
+}
+
+// Close stops the Python process. Requests made after Close fail with
+// ErrWorkerClosed until Start is called again.
+func (w *Worker) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	p := w.proc
+	w.proc = nil
+	w.mu.Unlock()
+	// Stopping may wait for the process to exit, which must not hold up
+	// other callers.
+	if p != nil {
+		p.stop()
+	}
+	return nil
+}
+
+// Parse parses s with packaging.version.Version.
+func (w *Worker) Parse(s string) (Version, error) {
+	return w.ParseContext(context.Background(), s)
+}
+
+// Compare compares two versions with packaging.version.Version.
+func (w *Worker) Compare(v1, v2 Version) (int, error) {
+	return w.CompareContext(context.Background(), v1, v2)
+}
+
+// ParseContext is like Parse but returns a *CanceledError if ctx is done
+// before the backend answers.
+func (w *Worker) ParseContext(ctx context.Context, s string) (Version, error) {
+	return w.parseLogged(ctx, w.logger(), s)
+}
+
+// CompareContext is like Compare but returns a *CanceledError if ctx is
+// done before the backend answers.
+func (w *Worker) CompareContext(ctx context.Context, v1, v2 Version) (int, error) {
+	return w.compareLogged(ctx, w.logger(), v1, v2)
+}
+
+func (w *Worker) parseLogged(ctx context.Context, log *slog.Logger, s string) (Version, error) {
+	res, err := w.call(ctx, log, "parse", s)
+	if err != nil {
+		return Version{Original: s}, err
+	}
+	return decodeBackendVersion(s, res)
+}
+
+func (w *Worker) compareLogged(ctx context.Context, log *slog.Logger, v1, v2 Version) (int, error) {
+	res, err := w.call(ctx, log, "compare", v1.Original, v2.Original)
+	if err != nil {
+		return 0, err
+	}
+	var c int
+	if err := json.Unmarshal(res, &c); err != nil {
+		return 0, fmt.Errorf("%w: unexpected compare output: %v", ErrBackend, err)
+	}
+	return c, nil
+}
+
+// SortStrings sorts version strings in increasing order in a single
+// request, like the package-level SortStrings. If any string is invalid, ss
+// is left unchanged and the error is returned.
+func (w *Worker) SortStrings(ss []string) error {
+	return w.SortStringsContext(context.Background(), ss)
+}
+
+// SortStringsContext is like SortStrings but returns a *CanceledError if
+// ctx is done before the backend answers, leaving ss unchanged.
+func (w *Worker) SortStringsContext(ctx context.Context, ss []string) error {
+	if len(ss) == 0 {
+		return nil
+	}
+	res, err := w.call(ctx, w.logger(), "sort", ss...)
+	if err != nil {
+		return err
+	}
+	var sorted []string
+	if err := json.Unmarshal(res, &sorted); err != nil || len(sorted) != len(ss) {
+		return fmt.Errorf("%w: unexpected sort output: %s", ErrBackend, res)
+	}
+	copy(ss, sorted)
+	return nil
+}
+
+// call sends one request and waits for its response or for ctx to be
+// done.
+func (w *Worker) call(ctx context.Context, log *slog.Logger, op string, args ...string) (json.RawMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &CanceledError{Op: op, Args: args, Err: err}
+	}
+	start := time.Now()
+	p, id, ch, err := w.register(log)
+	if err != nil {
+		return nil, err
+	}
+	line, err := json.Marshal(workerRequest{ID: id, Op: op, Args: args})
+	if err != nil {
+		p.forget(id)
+		return nil, fmt.Errorf("%w: encoding request: %v", ErrBackend, err)
+	}
+	// The request is offered to the writer goroutine until it takes it,
+	// so that a write blocked on a process that stopped reading does not
+	// hold up the wait for ctx.
+	writes, line := p.writes, append(line, '\n')
+	var seen uint64 // p.answered once the request was taken
+	var resp workerResponse
+wait:
+	for {
+		select {
+		case writes <- line:
+			writes, seen = nil, p.answered.Load()
+		case resp = <-ch:
+			break wait
+		case <-p.done:
+			// The response may have been delivered just before the exit.
+			select {
+			case resp = <-ch:
+				break wait
+			default:
+			}
+			p.forget(id)
+			log.Debug("pyver: backend failed", "op", op, "args", args, "error", p.err)
+			return nil, p.err
+		case <-ctx.Done():
+			p.forget(id)
+			// A request still waiting for the writer is stuck behind
+			// another, whose caller decides whether the process hangs.
+			if writes == nil && p.answered.Load() == seen {
+				log.Debug("pyver: stopping unresponsive backend worker", "pid", p.cmd.Process.Pid)
+				w.discard(p)
+			}
+			err := &CanceledError{Op: op, Args: args, Err: ctx.Err()}
+			log.Debug("pyver: backend failed", "op", op, "args", args, "error", err, "elapsed", time.Since(start))
+			return nil, err
+		}
+	}
+	log.Debug("pyver: backend call", "op", op, "args", args, "elapsed", time.Since(start))
+	switch {
+	case resp.Invalid != nil:
+		return nil, invalidVersionError(*resp.Invalid)
+	case resp.Error != "":
+		return nil, fmt.Errorf("%w: %s", ErrBackend, resp.Error)
+	}
+	return resp.Result, nil
+}
+
+// register assigns an id to a request for the running process, starting
+// it if needed, and returns the channel its response is delivered on. The
+// request itself is written by call, outside w.mu.
+func (w *Worker) register(log *slog.Logger) (*workerProc, uint64, chan workerResponse, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, 0, nil, ErrWorkerClosed
+	}
+	p, err := w.running(log)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	w.nextID++
+	id := w.nextID
+	ch := make(chan workerResponse, 1)
+	p.mu.Lock()
+	p.pending[id] = ch
+	p.mu.Unlock()
+	return p, id, ch, nil
+}
+
+// running returns the current process, replacing it if it has exited or
+// was started with a different command. w.mu must be held.
+func (w *Worker) running(log *slog.Logger) (*workerProc, error) {
+	args, logArgs := backendCommand("serve")
+	if p := w.proc; p != nil {
+		select {
+		case <-p.done:
+			log.Debug("pyver: restarting backend worker", "reason", p.err)
+			w.proc = nil
+		default:
+			if slices.Equal(p.args, args) {
+				return p, nil
+			}
+			log.Debug("pyver: restarting backend worker", "reason", "configuration changed")
+			// The old process finishes its requests in the background
+			// rather than under w.mu.
+			go p.stop()
+			w.proc = nil
+		}
+	}
+	p, err := startWorker(log, args)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("pyver: started backend worker", "command", logArgs, "pid", p.cmd.Process.Pid)
+	w.proc = p
+	return p, nil
+}
+
+// discard kills p and, if it is still the current process, forgets it.
+func (w *Worker) discard(p *workerProc) {
+	w.mu.Lock()
+	if w.proc == p {
+		w.proc = nil
+	}
+	w.mu.Unlock()
+	p.kill()
+}
+
+func startWorker(log *slog.Logger, args []string) (*workerProc, error) {
+	// The process outlives the request that starts it, so its context is
+	// canceled only to kill it.
+	ctx, kill := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	p := &workerProc{
+		args:     args,
+		cmd:      cmd,
+		kill:     kill,
+		writes:   make(chan []byte),
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+		pending:  make(map[uint64]chan workerResponse),
+	}
+	cmd.Stderr = &p.stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		kill()
+		return nil, fmt.Errorf("%w: %v", ErrBackend, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		kill()
+		return nil, fmt.Errorf("%w: %v", ErrBackend, err)
+	}
+	if err := cmd.Start(); err != nil {
+		kill()
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %q is not installed or not in PATH; set GO_PYTHON to a Python 3 interpreter", ErrPythonUnavailable, args[0])
+		}
+		return nil, fmt.Errorf("%w: %v", ErrBackend, err)
+	}
+	go p.write(stdin)
+	go p.read(log, stdout)
+	return p, nil
+}
+
+// write passes request lines to the process until it is stopped or exits.
+// A failed write means the process no longer reads its input, so it is
+// killed; read then reports why it exited.
+func (p *workerProc) write(stdin io.WriteCloser) {
+	defer stdin.Close()
+	for {
+		select {
+		case line := <-p.writes:
+			if _, err := stdin.Write(line); err != nil {
+				p.kill()
+				return
+			}
+		case <-p.stopping:
+			return
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// read delivers responses until the process closes its output, then
+// records why it exited.
+func (p *workerProc) read(log *slog.Logger, stdout io.Reader) {
+	r := bufio.NewReader(stdout)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var resp workerResponse
+			if jsonErr := json.Unmarshal(line, &resp); jsonErr != nil {
+				log.Debug("pyver: unexpected backend output", "line", string(line))
+			} else {
+				p.deliver(resp)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	err := p.cmd.Wait()
+	p.kill()
+	stderr := strings.TrimSpace(p.stderr.String())
+	var exit *exec.ExitError
+	switch {
+	case err == nil:
+		p.err = fmt.Errorf("%w: worker exited", ErrBackend)
+	case errors.As(err, &exit) && exit.ExitCode() == packagingMissingExit:
+		p.err = fmt.Errorf("%w to %q; install it with pip install packaging or set GO_PYTHON: %s", ErrPackagingUnavailable, strings.Join(getPythonArgs(), " "), stderr)
+	default:
+		p.err = fmt.Errorf("%w: worker exited: %v: %s", ErrBackend, err, stderr)
+	}
+	close(p.done)
+}
+
+func (p *workerProc) deliver(resp workerResponse) {
+	p.answered.Add(1)
+	p.mu.Lock()
+	ch := p.pending[resp.ID]
+	delete(p.pending, resp.ID)
+	p.mu.Unlock()
+	if ch != nil {
+		ch <- resp
+	}
+}
+
+func (p *workerProc) forget(id uint64) {
+	p.mu.Lock()
+	delete(p.pending, id)
+	p.mu.Unlock()
+}
+
+// stop closes the process's input, letting it answer outstanding requests
+// and exit, and kills it if it does not exit in time.
+func (p *workerProc) stop() {
+	close(p.stopping)
+	select {
+	case <-p.done:
+	case <-time.After(workerStopTimeout):
+		p.kill()
+		<-p.done
+	}
+}
+
+// backendCommand returns the command line running pyver_backend.py with
+// args, along with a shorter form for logging that omits the embedded
+// script.
+func backendCommand(args ...string) (cmd, logCmd []string) {
+	cmd = getPythonArgs()
+	logCmd = slices.Clone(cmd)
+	if BackendPath != "" {
+		cmd = append(cmd, BackendPath)
+		logCmd = append(logCmd, BackendPath)
+	} else {
+		cmd = append(cmd, "-c", backendScript)
+		logCmd = append(logCmd, "-c", "<embedded pyver_backend.py>")
+	}
+	return append(cmd, args...), append(logCmd, args...)
+}
@@ -0,0 +1,343 @@
+package pyver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestWorker returns a started Worker, skipping the test when the
+// interpreter or the packaging library is unavailable.
+func newTestWorker(t *testing.T) *Worker {
+	t.Helper()
+	w := &Worker{}
+	t.Cleanup(func() { w.Close() })
+	if _, err := w.Parse("1.0"); err != nil {
+		t.Skipf("python backend unavailable: %v", err)
+	}
+	return w
+}
+
+func TestWorker(t *testing.T) {
+	w := newTestWorker(t)
+	v, err := w.Parse("1.0-RC1")
+	if err != nil || v.Normalized != "1.0rc1" {
+		t.Errorf("Parse = %q, %v, want 1.0rc1", v.Normalized, err)
+	}
+	var pe *ParseError
+	if _, err := w.Parse("1.0+"); !errors.As(err, &pe) || pe.Reason != ReasonUnexpectedEnd {
+		t.Errorf("Parse(invalid) = %v, want a ParseError", err)
+	}
+	if c, err := w.Compare(MustParse("1.0a1"), MustParse("1.0")); err != nil || c != -1 {
+		t.Errorf("Compare = %d, %v, want -1", c, err)
+	}
+	tags := []string{"v1.10", "1.9", "1.10rc1", "1.9.0"}
+	if err := w.SortStrings(tags); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1.9", "1.9.0", "1.10rc1", "v1.10"}; !slices.Equal(tags, want) {
+		t.Errorf("SortStrings = %q, want %q", tags, want)
+	}
+	tags = []string{"2.0", "bogus"}
+	if err := w.SortStrings(tags); !errors.Is(err, ErrInvalidVersion) || tags[0] != "2.0" {
+		t.Errorf("SortStrings(invalid) = %v, %q", err, tags)
+	}
+}
+
+func TestWorkerConcurrent(t *testing.T) {
+	w := newTestWorker(t)
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := fmt.Sprintf("1.%d.dev%d", i, i)
+			v, err := w.Parse(s)
+			if err != nil || v.Normalized != s {
+				t.Errorf("Parse(%q) = %q, %v", s, v.Normalized, err)
+			}
+			if c, err := w.Compare(v, MustParse("1.0")); err != nil || c != Compare(v, MustParse("1.0")) {
+				t.Errorf("Compare(%q, 1.0) = %d, %v", s, c, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWorkerRestart(t *testing.T) {
+	w := newTestWorker(t)
+	w.mu.Lock()
+	p := w.proc
+	w.mu.Unlock()
+	p.cmd.Process.Kill()
+	<-p.done
+
+	v, err := w.Parse("2.0")
+	if err != nil || v.Normalized != "2.0" {
+		t.Fatalf("Parse after crash = %q, %v", v.Normalized, err)
+	}
+	w.mu.Lock()
+	restarted := w.proc != p
+	w.mu.Unlock()
+	if !restarted {
+		t.Error("worker was not restarted after a crash")
+	}
+}
+
+func TestWorkerClose(t *testing.T) {
+	w := newTestWorker(t)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Parse("1.0"); !errors.Is(err, ErrWorkerClosed) || !errors.Is(err, ErrBackend) {
+		t.Errorf("Parse after Close = %v, want ErrWorkerClosed", err)
+	}
+	if err := w.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Parse("1.0"); err != nil {
+		t.Errorf("Parse after Start = %v", err)
+	}
+}
+
+func TestWorkerContext(t *testing.T) {
+	w := &Worker{}
+	defer w.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := w.ParseContext(ctx, "1.0")
+	var ce *CanceledError
+	if !errors.As(err, &ce) || ce.Timeout() || !errors.Is(err, context.Canceled) || !errors.Is(err, ErrBackend) {
+		t.Errorf("ParseContext(canceled) = %v, want a CanceledError", err)
+	}
+	if w.proc != nil {
+		t.Error("ParseContext(canceled) started the backend")
+	}
+}
+
+func TestWorkerHungInterpreter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as the interpreter")
+	}
+	// An interpreter that never answers, like uv resolving packaging
+	// without network access.
+	v1, v2 := MustParse("1.0"), MustParse("2.0")
+	hang := filepath.Join(t.TempDir(), "hang")
+	if err := os.WriteFile(hang, []byte("#!/bin/sh\nexec sleep 60\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GO_PYTHON", hang)
+	w := &Worker{}
+	defer w.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := w.CompareContext(ctx, v1, v2)
+	var ce *CanceledError
+	if !errors.As(err, &ce) || !ce.Timeout() || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CompareContext = %v, want a timeout", err)
+	}
+	if ce.Op != "compare" || !slices.Equal(ce.Args, []string{"1.0", "2.0"}) {
+		t.Errorf("CanceledError = %+v", ce)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("CompareContext returned after %v", elapsed)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.proc != nil {
+		t.Error("the unresponsive process was kept")
+	}
+}
+
+func TestWorkerHangAfterReply(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as the interpreter")
+	}
+	// An interpreter that answers the first request and then stops
+	// responding.
+	v1, v2 := MustParse("1.0"), MustParse("2.0")
+	hang := filepath.Join(t.TempDir(), "hang")
+	script := "#!/bin/sh\nread line\necho '{\"id\":1,\"result\":-1}'\nexec sleep 60\n"
+	if err := os.WriteFile(hang, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GO_PYTHON", hang)
+	w := &Worker{}
+	defer w.Close()
+
+	if c, err := w.Compare(v1, v2); err != nil || c != -1 {
+		t.Fatalf("Compare = %d, %v, want -1", c, err)
+	}
+	w.mu.Lock()
+	p := w.proc
+	w.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := w.CompareContext(ctx, v1, v2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CompareContext = %v, want a timeout", err)
+	}
+	select {
+	case <-p.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the unresponsive process was not killed")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.proc != nil {
+		t.Error("the unresponsive process was kept")
+	}
+}
+
+func TestWorkerBlockedWrite(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as the interpreter")
+	}
+	// An interpreter that never reads its input, so that a batch larger
+	// than the pipe buffer cannot be written.
+	hang := filepath.Join(t.TempDir(), "hang")
+	if err := os.WriteFile(hang, []byte("#!/bin/sh\nexec sleep 60\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GO_PYTHON", hang)
+	w := &Worker{}
+	defer w.Close()
+
+	tags := make([]string, 100000)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("1.%d", len(tags)-i)
+	}
+	first := tags[0]
+	sorted := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		sorted <- w.SortStringsContext(ctx, tags)
+	}()
+	// Wait for the process that the batch is being written to.
+	for {
+		w.mu.Lock()
+		p := w.proc
+		w.mu.Unlock()
+		if p != nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := w.ParseContext(ctx, "1.0"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ParseContext = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ParseContext returned after %v, behind the blocked write", elapsed)
+	}
+	if err := <-sorted; !errors.Is(err, ErrBackend) || tags[0] != first {
+		t.Errorf("SortStringsContext = %v, %q..., want an error leaving the tags unchanged", err, tags[0])
+	}
+}
+
+func TestWorkerCancelShared(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as the interpreter")
+	}
+	// An interpreter that answers comparisons but never parses, and
+	// records that it has read a parse request.
+	dir := t.TempDir()
+	script := `#!/bin/sh
+while read line; do
+	case $line in
+	*'"op":"parse"'*) : > "$0.parse" ;;
+	*) id=${line#*'"id":'}; echo "{\"id\":${id%%,*},\"result\":-1}" ;;
+	esac
+done
+`
+	python := filepath.Join(dir, "python")
+	if err := os.WriteFile(python, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GO_PYTHON", python)
+	w := &Worker{}
+	defer w.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	parsed := make(chan error)
+	go func() {
+		_, err := w.ParseContext(ctx, "1.0")
+		parsed <- err
+	}()
+	for {
+		if _, err := os.Stat(python + ".parse"); err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	v1, v2 := MustParse("1.0"), MustParse("2.0")
+	if c, err := w.Compare(v1, v2); err != nil || c != -1 {
+		t.Fatalf("Compare = %d, %v, want -1", c, err)
+	}
+	w.mu.Lock()
+	p := w.proc
+	w.mu.Unlock()
+
+	// The process answered since the parse request was written, so
+	// canceling it leaves the process to the other requests.
+	cancel()
+	if err := <-parsed; !errors.Is(err, context.Canceled) {
+		t.Fatalf("ParseContext = %v, want a cancellation", err)
+	}
+	if c, err := w.Compare(v1, v2); err != nil || c != -1 {
+		t.Errorf("Compare after the cancellation = %d, %v, want -1", c, err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.proc != p {
+		t.Error("canceling one request replaced the shared process")
+	}
+}
+
+func TestWorkerUnavailable(t *testing.T) {
+	t.Setenv("GO_PYTHON", "nonexistent-pyver-python")
+	w := &Worker{}
+	defer w.Close()
+	if _, err := w.Parse("1.0"); !errors.Is(err, ErrPythonUnavailable) || !errors.Is(err, ErrBackend) {
+		t.Errorf("Parse with a missing interpreter = %v, want ErrPythonUnavailable", err)
+	}
+
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not found")
+	}
+	// -I -S leaves out site-packages, where packaging is installed.
+	t.Setenv("GO_PYTHON", python+" -I -S")
+	_, err = w.Parse("1.0")
+	if err == nil {
+		t.Skip("packaging is importable without site-packages")
+	}
+	if !errors.Is(err, ErrPackagingUnavailable) || !errors.Is(err, ErrBackend) {
+		t.Errorf("Parse without packaging = %v, want ErrPackagingUnavailable", err)
+	}
+}
+
+func TestWorkerMissingScript(t *testing.T) {
+	orig := BackendPath
+	BackendPath = "nonexistent_pyver_backend.py"
+	defer func() { BackendPath = orig }()
+	w := &Worker{}
+	defer w.Close()
+	if _, err := w.Parse("1.0"); !errors.Is(err, ErrBackend) {
+		t.Errorf("Parse with a missing script = %v, want ErrBackend", err)
+	}
+}